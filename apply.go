@@ -0,0 +1,14 @@
+package golden
+
+import "fmt"
+
+func dagApply(b Block) error {
+	ab, ok := b.(ApplyBlock)
+	if !ok {
+		return nil
+	}
+	if err := withRetry(b, applyPhase, ab.Apply); err != nil {
+		return fmt.Errorf("%s(%s) apply error: %+v", b.Address(), b.HclBlock().Range().String(), err)
+	}
+	return postConditionCheck(b)
+}
@@ -5,21 +5,28 @@ import (
 	"github.com/google/uuid"
 	"github.com/hashicorp/go-multierror"
 	"github.com/hashicorp/hcl/v2"
-	"github.com/hashicorp/hcl/v2/gohcl"
 	"github.com/hashicorp/hcl/v2/hclsyntax"
 	"github.com/zclconf/go-cty/cty"
 )
 
 type BaseBlock struct {
-	c             Config
-	hb            *HclBlock
-	name          string
-	id            string
-	blockAddress  string
-	forEach       *ForEach
-	hasExpanded   bool
-	readyForRead  bool
-	preConditions []PreCondition
+	c              Config
+	hb             *HclBlock
+	name           string
+	id             string
+	blockAddress   string
+	forEach        *ForEach
+	hasExpanded    bool
+	readyForRead   bool
+	preConditions  []PreCondition
+	postConditions []PostCondition
+
+	lifecycleParsed bool
+	parsedLifecycle *Lifecycle
+	lifecycleErr    error
+
+	planAttempts  int
+	applyAttempts int
 }
 
 func NewBaseBlock(c Config, hb *HclBlock) *BaseBlock {
@@ -103,7 +110,23 @@ func (bb *BaseBlock) PreConditionCheck(ctx *hcl.EvalContext) ([]PreCondition, er
 	var failedChecks []PreCondition
 	var err error
 	for _, cond := range bb.preConditions {
-		diag := gohcl.DecodeBody(cond.Body, ctx, &cond)
+		diag := decodeBody(cond.Body, ctx, &cond)
+		if diag.HasErrors() {
+			err = multierror.Append(err, diag.Errs()...)
+			continue
+		}
+		if !cond.Condition {
+			failedChecks = append(failedChecks, cond)
+		}
+	}
+	return failedChecks, err
+}
+
+func (bb *BaseBlock) PostConditionCheck(ctx *hcl.EvalContext) ([]PostCondition, error) {
+	var failedChecks []PostCondition
+	var err error
+	for _, cond := range bb.postConditions {
+		diag := decodeBody(cond.Body, ctx, &cond)
 		if diag.HasErrors() {
 			err = multierror.Append(err, diag.Errs()...)
 			continue
@@ -147,6 +170,11 @@ func (bb *BaseBlock) setMetaNestedBlock() {
 				Body: nb.Body,
 			})
 		}
+		if nb.Type == "postcondition" {
+			bb.postConditions = append(bb.postConditions, PostCondition{
+				Body: nb.Body,
+			})
+		}
 	}
 }
 
@@ -165,3 +193,26 @@ func (bb *BaseBlock) markReady() {
 func (bb *BaseBlock) isReadyForRead() bool {
 	return bb.readyForRead
 }
+
+// restoreId sets the id saved in a plan file, so references to `id` stay the same between plan and apply.
+func (bb *BaseBlock) restoreId(id string) {
+	bb.id = id
+}
+
+func (bb *BaseBlock) setAttempts(phase string, attempts int) {
+	if phase == planPhase {
+		bb.planAttempts = attempts
+		return
+	}
+	bb.applyAttempts = attempts
+}
+
+// PlanAttempts returns how many times ExecuteDuringPlan has been executed in the last plan, including retries.
+func (bb *BaseBlock) PlanAttempts() int {
+	return bb.planAttempts
+}
+
+// ApplyAttempts returns how many times Apply has been executed in the last apply, including retries.
+func (bb *BaseBlock) ApplyAttempts() int {
+	return bb.applyAttempts
+}
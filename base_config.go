@@ -4,14 +4,15 @@ import (
 	"context"
 	"fmt"
 	"github.com/zclconf/go-cty/cty/function"
+	"log/slog"
 	"path/filepath"
 	"sort"
 	"sync"
+	"time"
 
 	"github.com/hashicorp/hcl/v2"
 	"github.com/lonegunmanb/hclfuncs"
 	"github.com/spf13/afero"
-	"github.com/zclconf/go-cty/cty"
 )
 
 var configFs = afero.NewOsFs()
@@ -24,11 +25,48 @@ type NewBaseConfigArgs struct {
 	DslFullName              string
 	IgnoreUnknownVariables   bool
 	CliFlagAssignedVariables []CliFlagAssignedVariables
+	// Layers are ordered config directories for LoadLayeredHclBlocks, Basedir defaults to the last layer.
+	Layers []string
+	// Sandbox confines filesystem functions when it's not nil, it should be set for untrusted configs.
+	Sandbox *Sandbox
+	// Workspace selects `<workspace>.<abbr>vars` files and the artifact directory, it's `default` when empty.
+	Workspace string
+	// StatePath enables the local state file, a relative path like `state.json` is resolved under WorkspaceDir so workspaces keep their own states.
+	StatePath string
+	// SavedPlanPath makes variables read from a plan file written by SavePlan instead of env, var files, command line flags and prompts,
+	// so the config could be initialized without variable inputs before LoadPlan is called with the same path. A relative path is resolved under WorkspaceDir like SavePlan.
+	SavedPlanPath string
+	// RetryPolicy applies to blocks without `retry` meta block, nil means no retry.
+	RetryPolicy *RetryPolicy
+	// Hooks observe the execution from config initialization, see AddHook.
+	Hooks []Hook
+	// Tracer and Meter receive spans and metrics of runs, nil disables them.
+	Tracer Tracer
+	Meter  Meter
+	// Logger receives debug records of variable resolution, dag construction, `for_each` expansion and block execution, nil discards them.
+	Logger *slog.Logger
 }
 
 type BaseConfig struct {
-	ctx                      context.Context
-	basedir                  string
+	ctx        context.Context
+	basedir    string
+	rootDir    string
+	workspace  string
+	planErrors map[string]error
+	planOrder  []string
+	statePath  string
+	// savedPlanPath is the plan file that input variables are read from, see NewBaseConfigArgs.SavedPlanPath.
+	savedPlanPath string
+	priorState    *State
+	retryPolicy   *RetryPolicy
+	hooks         []Hook
+	tracer        Tracer
+	meter         Meter
+	logger        *slog.Logger
+	// spanCtx carries the innermost span while a run is traced, phase is the traced run's name.
+	spanCtx                  context.Context
+	phase                    string
+	layers                   []string
 	varConfigDir             *string
 	d                        *Dag
 	rawBlockAddresses        map[string]struct{}
@@ -38,7 +76,17 @@ type BaseConfig struct {
 	inputVariables           map[string]VariableValueRead
 	inputVariableReadsLoader *sync.Once
 	ignoreUnknownVariables   bool
-	OverrideFunctions        map[string]function.Function
+	// moduleArguments are the only input variables of a config loaded by a `module` block.
+	moduleArguments map[string]VariableValueRead
+	// moduleDirs are directories of the calling modules chain, used to detect recursive modules.
+	moduleDirs []string
+	// modulePath is like `module.a.module.b` for configs loaded by `module` blocks, it's empty for the root config.
+	modulePath          string
+	registeredFunctions map[string]function.Function
+	builtinOverrides    map[string]function.Function
+	sandbox             *Sandbox
+	// OverrideFunctions adds functions without namespace, InitConfig rejects built-in names, use OverrideBuiltinFunction to replace built-in functions.
+	OverrideFunctions map[string]function.Function
 }
 
 func (c *BaseConfig) Context() context.Context {
@@ -50,8 +98,9 @@ func (c *BaseConfig) DslAbbreviation() string { return c.dslAbbreviation }
 
 func (c *BaseConfig) EmptyEvalContext() *hcl.EvalContext {
 	return &hcl.EvalContext{
-		Functions: merge(hclfuncs.Functions(c.basedir), c.OverrideFunctions),
-		Variables: make(map[string]cty.Value),
+		// sandboxed functions are merged last, so no override could escape the sandbox.
+		Functions: merge(hclfuncs.Functions(c.basedir), c.overrideFunctions(), c.builtinOverrides, c.registeredFunctions, c.userFunctions(), c.sandboxFunctions()),
+		Variables: c.builtinValues(),
 	}
 }
 
@@ -72,17 +121,44 @@ func (c *BaseConfig) EvalContext() *hcl.EvalContext {
 }
 
 func NewBasicConfigFromArgs(a NewBaseConfigArgs) *BaseConfig {
-	c := NewBasicConfig(a.Basedir, a.DslFullName, a.DslAbbreviation, a.VarConfigDir, a.CliFlagAssignedVariables, a.Ctx)
+	basedir := a.Basedir
+	if basedir == "" && len(a.Layers) > 0 {
+		basedir = a.Layers[len(a.Layers)-1]
+	}
+	c := NewBasicConfig(basedir, a.DslFullName, a.DslAbbreviation, a.VarConfigDir, a.CliFlagAssignedVariables, a.Ctx)
 	c.ignoreUnknownVariables = a.IgnoreUnknownVariables
+	c.layers = a.Layers
+	c.sandbox = a.Sandbox
+	c.workspace = a.Workspace
+	c.statePath = a.StatePath
+	c.savedPlanPath = a.SavedPlanPath
+	c.retryPolicy = a.RetryPolicy
+	c.hooks = a.Hooks
+	if a.Tracer != nil {
+		c.tracer = a.Tracer
+	}
+	if a.Meter != nil {
+		c.meter = a.Meter
+	}
+	c.logger = a.Logger
 	return c
 }
 
+// Layers returns config directories in order, blocks should be loaded by LoadLayeredHclBlocks.
+func (c *BaseConfig) Layers() []string {
+	if len(c.layers) == 0 {
+		return []string{c.basedir}
+	}
+	return c.layers
+}
+
 func NewBasicConfig(basedir, dslFullName, dslAbbreviation string, varConfigDir *string, cliFlagAssignedVariables []CliFlagAssignedVariables, ctx context.Context) *BaseConfig {
 	if ctx == nil {
 		ctx = context.Background()
 	}
 	c := &BaseConfig{
 		basedir:                  basedir,
+		rootDir:                  basedir,
 		varConfigDir:             varConfigDir,
 		ctx:                      ctx,
 		dslAbbreviation:          dslAbbreviation,
@@ -91,16 +167,46 @@ func NewBasicConfig(basedir, dslFullName, dslAbbreviation string, varConfigDir *
 		d:                        newDag(),
 		inputVariableReadsLoader: &sync.Once{},
 		rawBlockAddresses:        make(map[string]struct{}),
+		tracer:                   nopTracer{},
+		meter:                    nopMeter{},
 	}
 	return c
 }
 
 func (c *BaseConfig) RunPrePlan() error {
-	return c.runDag(prePlan)
+	return c.traceRun("pre_plan", func() error {
+		return c.runDag(prePlan)
+	})
 }
 
 func (c *BaseConfig) RunPlan() error {
-	return c.runDag(dagPlan)
+	if err := c.loadPriorState(); err != nil {
+		return err
+	}
+	c.planErrors = make(map[string]error)
+	c.planOrder = nil
+	return c.traceRun("plan", func() error {
+		return c.runDag(func(b Block) error {
+			err := dagPlan(b)
+			c.planErrors[b.Address()] = err
+			c.planOrder = append(c.planOrder, b.Address())
+			return err
+		})
+	})
+}
+
+// RunApply applies all ApplyBlock in dependency order, it must be called after RunPlan.
+func (c *BaseConfig) RunApply() error {
+	return c.traceRun("apply", func() error {
+		if c.statePath != "" {
+			return c.applyWithState()
+		}
+		return c.runDag(dagApply)
+	})
+}
+
+func (c *BaseConfig) baseConfig() *BaseConfig {
+	return c
 }
 
 func (c *BaseConfig) GetVertices() map[string]interface{} {
@@ -132,8 +238,15 @@ func (c *BaseConfig) readInputVariables() (map[string]VariableValueRead, error)
 	if c.inputVariables != nil {
 		return c.inputVariables, nil
 	}
+	if c.moduleArguments != nil {
+		return c.moduleArguments, nil
+	}
 	var readErr error
 	c.inputVariableReadsLoader.Do(func() {
+		if c.savedPlanPath != "" {
+			c.inputVariables, readErr = c.readVariablesFromSavedPlan(c.savedPlanPath)
+			return
+		}
 		envVars := c.readVariablesFromEnv()
 		defaultFileVars, err := c.readVariablesFromDefaultVarFiles()
 		if err != nil {
@@ -150,7 +263,7 @@ func (c *BaseConfig) readInputVariables() (map[string]VariableValueRead, error)
 			readErr = err
 			return
 		}
-		c.inputVariables = merge(envVars, defaultFileVars, autoFileVars, cliAssignedVariables)
+		c.inputVariables = mergeVariableReads(envVars, defaultFileVars, autoFileVars, cliAssignedVariables)
 	})
 	return c.inputVariables, readErr
 }
@@ -166,37 +279,49 @@ func (c *BaseConfig) readVariablesFromEnv() map[string]VariableValueRead {
 
 func (c *BaseConfig) readCliAssignedVariables() (map[string]VariableValueRead, error) {
 	r := make(map[string]VariableValueRead)
-	for _, assignedVariables := range c.cliFlagAssignedVariables {
+	for i, assignedVariables := range c.cliFlagAssignedVariables {
 		reads, err := assignedVariables.Variables(c)
 		if err != nil {
 			return nil, err
 		}
-		r = merge(r, reads)
+		for n, read := range reads {
+			read.Source.CliFlagIndex = i
+			reads[n] = read
+		}
+		r = mergeVariableReads(r, reads)
 	}
 	return r, nil
 }
 
 func (c *BaseConfig) readVariablesFromAutoVarFiles() (map[string]VariableValueRead, error) {
-	autoHclVarFilePattern := fmt.Sprintf("*.auto.%svars", c.dslAbbreviation)
-	autoJsonVarFilePattern := autoHclVarFilePattern + ".json"
-
-	hclMatches, err := afero.Glob(configFs, filepath.Join(c.variableConfigFilesDir(), autoHclVarFilePattern))
-	if err != nil {
-		return nil, fmt.Errorf("cannot list auto var files at %s: %+v", c.variableConfigFilesDir(), err)
+	autoVarFilePattern := fmt.Sprintf("*.auto.%svars", c.dslAbbreviation)
+	var matches []string
+	for _, suffix := range varFileSuffixes {
+		m, err := afero.Glob(configFs, filepath.Join(c.variableConfigFilesDir(), autoVarFilePattern+suffix))
+		if err != nil {
+			return nil, fmt.Errorf("cannot list auto var files at %s: %+v", c.variableConfigFilesDir(), err)
+		}
+		matches = append(matches, m...)
 	}
-	jsonMatches, err := afero.Glob(configFs, filepath.Join(c.variableConfigFilesDir(), autoJsonVarFilePattern))
+	sort.Strings(matches)
+	reads, err := c.readVariablesFromVarFiles(matches)
 	if err != nil {
-		return nil, fmt.Errorf("cannot list auto var files at %s: %+v", c.variableConfigFilesDir(), err)
+		return nil, err
 	}
-	matches := append(hclMatches, jsonMatches...)
-	sort.Strings(matches)
-	return c.readVariablesFromVarFiles(matches)
+	return withSourceType(reads, VariableValueSourceAutoVarFile), nil
 }
 
 func (c *BaseConfig) readVariablesFromDefaultVarFiles() (map[string]VariableValueRead, error) {
-	defaultHclVarFilePath := filepath.Join(c.variableConfigFilesDir(), fmt.Sprintf("%s.%svars", c.dslFullName, c.dslAbbreviation))
-	defaultJsonVarFilePath := defaultHclVarFilePath + ".json"
-	return c.readVariablesFromVarFiles([]string{defaultHclVarFilePath, defaultJsonVarFilePath})
+	defaultVarFilePath := filepath.Join(c.variableConfigFilesDir(), fmt.Sprintf("%s.%svars", c.dslFullName, c.dslAbbreviation))
+	var paths []string
+	for _, suffix := range varFileSuffixes {
+		paths = append(paths, defaultVarFilePath+suffix)
+	}
+	workspacePaths, err := c.workspaceVarFilePaths()
+	if err != nil {
+		return nil, err
+	}
+	return c.readVariablesFromVarFiles(append(paths, workspacePaths...))
 }
 
 func (c *BaseConfig) readVariablesFromVarFiles(paths []string) (map[string]VariableValueRead, error) {
@@ -206,7 +331,7 @@ func (c *BaseConfig) readVariablesFromVarFiles(paths []string) (map[string]Varia
 		if err != nil {
 			return nil, err
 		}
-		r = merge(r, vars)
+		r = mergeVariableReads(r, vars)
 	}
 	return r, nil
 }
@@ -249,7 +374,11 @@ func (c *BaseConfig) ReadVariablesFromSingleVarFile(fileContent []byte, fileName
 		if diag.HasErrors() {
 			err = diag
 		}
-		reads[attr.Name] = NewVariableValueRead(attr.Name, &value, err)
+		reads[attr.Name] = NewVariableValueRead(attr.Name, &value, err).withSource(VariableValueSource{
+			Type:     VariableValueSourceVarFile,
+			FileName: fileName,
+			Range:    attr.Range,
+		})
 	}
 
 	return reads, nil
@@ -279,7 +408,18 @@ func (c *BaseConfig) buildDag(blocks []Block) error {
 }
 
 func (c *BaseConfig) runDag(onReady func(Block) error) error {
-	return c.d.runDag(c, onReady)
+	return c.d.runDag(c, func(b Block) error {
+		logger := c.Logger().With("address", b.Address(), "phase", c.phase)
+		logger.Debug("executing block")
+		start := time.Now()
+		err := c.traceBlock(b, onReady)
+		if err != nil {
+			logger.Debug("block failed", "duration", time.Since(start), "error", err)
+			return err
+		}
+		logger.Debug("block executed", "duration", time.Since(start))
+		return nil
+	})
 }
 
 func (c *BaseConfig) expandBlock(b Block) ([]Block, error) {
@@ -289,7 +429,9 @@ func (c *BaseConfig) expandBlock(b Block) ([]Block, error) {
 	if !ok || b.getForEach() != nil {
 		return nil, nil
 	}
+	start := time.Now()
 	forEachValue, diag := attr.Expr.Value(c.EvalContext())
+	recordDuration(b, MetricExpressionDuration, start, Attr("golden.expression.kind", "for_each"))
 	if diag.HasErrors() {
 		return nil, diag
 	}
@@ -334,6 +476,11 @@ func (c *BaseConfig) expandBlock(b Block) ([]Block, error) {
 		}
 	}
 	b.markExpanded()
+	instances := make([]string, 0, len(expandedBlocks))
+	for _, eb := range expandedBlocks {
+		instances = append(instances, eb.Address())
+	}
+	c.Logger().Debug("expanded block by for_each", "address", address, "instances", instances)
 	return expandedBlocks, c.d.DeleteVertex(address)
 }
 
@@ -80,6 +80,89 @@ obj_value = {
 				})), nil),
 			},
 		},
+		{
+			desc:     "valid yaml config",
+			filename: "terraform.tfvars.yaml",
+			content: `string_value: hello
+bool_value: true
+obj_value:
+  name: John Doe
+  gender: Male
+`,
+			expected: map[string]VariableValueRead{
+				"string_value": NewVariableValueRead("string_value", p(cty.StringVal("hello")), nil),
+				"bool_value":   NewVariableValueRead("bool_value", p(cty.True), nil),
+				"obj_value": NewVariableValueRead("obj_value", p(cty.ObjectVal(map[string]cty.Value{
+					"name":   cty.StringVal("John Doe"),
+					"gender": cty.StringVal("Male"),
+				})), nil),
+			},
+		},
+		{
+			desc:     "valid yml config",
+			filename: "terraform.tfvars.yml",
+			content:  `string_value: hello`,
+			expected: map[string]VariableValueRead{
+				"string_value": NewVariableValueRead("string_value", p(cty.StringVal("hello")), nil),
+			},
+		},
+		{
+			desc:     "yaml numbers and lists",
+			filename: "terraform.tfvars.yaml",
+			content: `number_value: 1.5
+list_value:
+  - a
+  - 2
+`,
+			expected: map[string]VariableValueRead{
+				"number_value": NewVariableValueRead("number_value", p(cty.MustParseNumberVal("1.5")), nil),
+				"list_value": NewVariableValueRead("list_value", p(cty.TupleVal([]cty.Value{
+					cty.StringVal("a"),
+					cty.MustParseNumberVal("2"),
+				})), nil),
+			},
+		},
+		{
+			desc:     "empty yaml config",
+			filename: "terraform.tfvars.yaml",
+			content:  "# comment only\n",
+			expected: map[string]VariableValueRead{},
+		},
+		{
+			desc:          "invalid yaml config",
+			filename:      "terraform.tfvars.yaml",
+			content:       "string_value: [hello",
+			expectedError: true,
+		},
+		{
+			desc:     "valid dotenv config",
+			filename: ".env",
+			content: `# comment
+string_value=hello
+export bool_value=true
+number_value = 1
+quoted_value="true"
+single_quoted_value='${hello}'
+list_value=["a", "b"]
+`,
+			expected: map[string]VariableValueRead{
+				"string_value":        NewVariableValueRead("string_value", p(cty.StringVal("hello")), nil),
+				"bool_value":          NewVariableValueRead("bool_value", p(cty.True), nil),
+				"number_value":        NewVariableValueRead("number_value", p(cty.MustParseNumberVal("1")), nil),
+				"quoted_value":        NewVariableValueRead("quoted_value", p(cty.StringVal("true")), nil),
+				"single_quoted_value": NewVariableValueRead("single_quoted_value", p(cty.StringVal("${hello}")), nil),
+				"list_value": NewVariableValueRead("list_value", p(cty.TupleVal([]cty.Value{
+					cty.StringVal("a"),
+					cty.StringVal("b"),
+				})), nil),
+			},
+		},
+		{
+			desc:          "invalid dotenv config",
+			filename:      "terraform.tfvars.env",
+			content:       "invalid line",
+			expectedError: true,
+		},
 	}
 	for _, c := range cases {
 		s.Run(c.desc, func() {
@@ -104,6 +187,18 @@ obj_value = {
 	}
 }
 
+func (s *baseConfigSuite) TestReadVarsFromDotEnvFile_RangeShouldPointToOriginalLine() {
+	sut := &BaseConfig{
+		dslFullName:     "terraform",
+		dslAbbreviation: "tf",
+	}
+	vars, err := sut.ReadVariablesFromSingleVarFile([]byte("# comment\n\nstring_value=hello\n"), "/config/.env")
+	require.NoError(s.T(), err)
+	read := vars["string_value"]
+	s.Equal(3, read.Source.Range.Start.Line)
+	s.Equal("/config/.env", read.Source.FileName)
+}
+
 func (s *baseConfigSuite) TestBaseConfig_ReadVariablesFromDefaultVarFiles() {
 	cases := []struct {
 		desc         string
@@ -180,6 +275,49 @@ string_value = "hello"
 				s.Equal("world", vars["string_value"].Value.AsString())
 			},
 		},
+		{
+			desc: "yaml vars should take precedence over json vars",
+			files: map[string]string{
+				"/terraform.tfvars.json": `{
+    "string_value": "world"
+}`,
+				"/terraform.tfvars.yaml": `string_value: hello`,
+			},
+			assert: func(vars map[string]VariableValueRead) {
+				s.Len(vars, 1)
+				s.Equal("hello", vars["string_value"].Value.AsString())
+				s.Equal("/terraform.tfvars.yaml", vars["string_value"].Source.FileName)
+			},
+		},
+		{
+			desc: "yaml var ranges should point to yaml keys",
+			files: map[string]string{
+				"/terraform.tfvars.yaml": `# comment
+
+a: 1
+nested:
+  b: 2
+`,
+			},
+			assert: func(vars map[string]VariableValueRead) {
+				s.Len(vars, 2)
+				s.Equal(3, vars["a"].Source.Range.Start.Line)
+				s.Equal(1, vars["a"].Source.Range.Start.Column)
+				s.Equal(4, vars["nested"].Source.Range.Start.Line)
+				s.Equal("var file /terraform.tfvars.yaml:3,1", vars["a"].Source.String())
+			},
+		},
+		{
+			desc: "dotenv vars should take precedence over yaml vars",
+			files: map[string]string{
+				"/terraform.tfvars.yml": `string_value: hello`,
+				"/terraform.tfvars.env": `string_value=world`,
+			},
+			assert: func(vars map[string]VariableValueRead) {
+				s.Len(vars, 1)
+				s.Equal("world", vars["string_value"].Value.AsString())
+			},
+		},
 		{
 			desc: "default vars in other folder",
 			files: map[string]string{
@@ -235,6 +373,24 @@ func (s *baseConfigSuite) TestReadVarsFromAutoVarFile() {
 				"string_value": NewVariableValueRead("string_value", p(cty.StringVal("hello")), nil),
 			},
 		},
+		{
+			desc: "valid yaml config",
+			files: map[string]string{
+				"/a.auto.tfvars.yaml": `string_value: hello`,
+			},
+			expected: map[string]VariableValueRead{
+				"string_value": NewVariableValueRead("string_value", p(cty.StringVal("hello")), nil),
+			},
+		},
+		{
+			desc: "valid dotenv config",
+			files: map[string]string{
+				"/a.auto.tfvars.env": `string_value=hello`,
+			},
+			expected: map[string]VariableValueRead{
+				"string_value": NewVariableValueRead("string_value", p(cty.StringVal("hello")), nil),
+			},
+		},
 		{
 			desc: "valid hcl config in other folder",
 			files: map[string]string{
@@ -421,6 +577,30 @@ func (s *baseConfigSuite) TestReadCliAssignedVariables() {
 				"string_value": NewVariableValueRead("string_value", p(cty.StringVal("hello")), nil),
 			},
 		},
+		{
+			desc: "CliFlagAssignedVariableFile-yaml",
+			cliFlags: []CliFlagAssignedVariables{
+				NewCliFlagAssignedVariableFile("/test.tfvars.yaml"),
+			},
+			files: map[string]string{
+				"/test.tfvars.yaml": `string_value: hello`,
+			},
+			expected: map[string]VariableValueRead{
+				"string_value": NewVariableValueRead("string_value", p(cty.StringVal("hello")), nil),
+			},
+		},
+		{
+			desc: "CliFlagAssignedVariableFile-dotenv",
+			cliFlags: []CliFlagAssignedVariables{
+				NewCliFlagAssignedVariableFile("/.env"),
+			},
+			files: map[string]string{
+				"/.env": `string_value="hello"`,
+			},
+			expected: map[string]VariableValueRead{
+				"string_value": NewVariableValueRead("string_value", p(cty.StringVal("hello")), nil),
+			},
+		},
 		{
 			desc: "CliFlagAssignedVariableFile-precedence-0",
 			cliFlags: []CliFlagAssignedVariables{
@@ -5,7 +5,6 @@ import (
 	"fmt"
 	"github.com/emirpasic/gods/sets/hashset"
 	"github.com/hashicorp/hcl/v2"
-	"github.com/hashicorp/hcl/v2/gohcl"
 	"github.com/hashicorp/hcl/v2/hclsyntax"
 	"github.com/lonegunmanb/go-defaults"
 	"github.com/zclconf/go-cty/cty"
@@ -31,6 +30,7 @@ type Block interface {
 	EvalContext() *hcl.EvalContext
 	BaseValues() map[string]cty.Value
 	PreConditionCheck(*hcl.EvalContext) ([]PreCondition, error)
+	PostConditionCheck(*hcl.EvalContext) ([]PostCondition, error)
 	AddressLength() int
 	CanExecutePrePlan() bool
 	Config() Config
@@ -51,7 +51,7 @@ func BlockToString(f Block) string {
 }
 
 var MetaAttributeNames = hashset.New("for_each", "depends_on")
-var MetaNestedBlockNames = hashset.New("precondition", "dynamic")
+var MetaNestedBlockNames = hashset.New("precondition", "postcondition", "dynamic", "lifecycle", "retry")
 
 func Decode(b Block) error {
 	hb := b.HclBlock()
@@ -75,7 +75,7 @@ func Decode(b Block) error {
 		return err
 	}
 
-	diag := gohcl.DecodeBody(cleanBodyForDecode(expandedHb.Body), evalContext, b)
+	diag := decodeBody(cleanBodyForDecode(expandedHb.Body), evalContext, b)
 	if diag.HasErrors() {
 		return diag
 	}
@@ -156,8 +156,20 @@ func SingleValues(blocks []SingleValueBlock) cty.Value {
 		return cty.EmptyObjectVal
 	}
 	res := map[string]cty.Value{}
+	instances := map[string]map[string]cty.Value{}
 	for _, b := range blocks {
-		res[b.Name()] = b.Value()
+		forEach := b.getForEach()
+		if forEach == nil {
+			res[b.Name()] = b.Value()
+			continue
+		}
+		if _, ok := instances[b.Name()]; !ok {
+			instances[b.Name()] = map[string]cty.Value{}
+		}
+		instances[b.Name()][CtyValueToString(forEach.key)] = b.Value()
+	}
+	for n, m := range instances {
+		res[n] = cty.ObjectVal(m)
 	}
 	return cty.ObjectVal(res)
 }
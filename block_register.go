@@ -63,6 +63,10 @@ func IsBlockTypeWanted(bt string) bool {
 func registerCommonBlock() {
 	RegisterBlock(new(LocalBlock))
 	RegisterBlock(new(VariableBlock))
+	RegisterBlock(new(CheckBlock))
+	RegisterBlock(new(OutputBlock))
+	RegisterBlock(new(ModuleBlock))
+	RegisterBlock(new(FunctionBlock))
 }
 
 var factories = map[string]blockRegistry{}
@@ -852,6 +852,10 @@ func (c fakeBlock) PreConditionCheck(context *hcl.EvalContext) ([]PreCondition,
 	panic("implement me")
 }
 
+func (c fakeBlock) PostConditionCheck(context *hcl.EvalContext) ([]PostCondition, error) {
+	panic("implement me")
+}
+
 func (c fakeBlock) AddressLength() int {
 	panic("implement me")
 }
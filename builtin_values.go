@@ -0,0 +1,47 @@
+package golden
+
+import (
+	"os"
+
+	"github.com/zclconf/go-cty/cty"
+)
+
+// defaultWorkspace is the workspace name when no workspace is selected.
+const defaultWorkspace = "default"
+
+// builtinValues are objects in every eval context that are not blocks: `path` and `<dsl full name>`.
+func (c *BaseConfig) builtinValues() map[string]cty.Value {
+	cwd, _ := os.Getwd()
+	r := map[string]cty.Value{
+		"path": cty.ObjectVal(map[string]cty.Value{
+			"module": cty.StringVal(c.basedir),
+			"root":   cty.StringVal(c.rootDir),
+			"cwd":    cty.StringVal(cwd),
+		}),
+	}
+	if c.dslFullName != "" {
+		r[c.dslFullName] = cty.ObjectVal(map[string]cty.Value{
+			"workspace": cty.StringVal(c.Workspace()),
+		})
+	}
+	return r
+}
+
+// Workspace returns the selected workspace name.
+func (c *BaseConfig) Workspace() string {
+	if c.workspace == "" {
+		return defaultWorkspace
+	}
+	return c.workspace
+}
+
+// builtinRootNames returns root names of builtinValues, references to them are not dependencies between blocks.
+func builtinRootNames(c Config) map[string]struct{} {
+	r := map[string]struct{}{
+		"path": {},
+	}
+	if c != nil && c.DslFullName() != "" {
+		r[c.DslFullName()] = struct{}{}
+	}
+	return r
+}
@@ -0,0 +1,141 @@
+package golden
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"github.com/zclconf/go-cty/cty"
+)
+
+type builtinValuesSuite struct {
+	suite.Suite
+	*testBase
+}
+
+func TestBuiltinValuesSuite(t *testing.T) {
+	suite.Run(t, new(builtinValuesSuite))
+}
+
+func (s *builtinValuesSuite) SetupTest() {
+	s.testBase = newTestBase()
+}
+
+func (s *builtinValuesSuite) TearDownTest() {
+	s.teardown()
+}
+
+func (s *builtinValuesSuite) TestPathAndWorkspaceValues() {
+	s.dummyFsWithFiles(map[string]string{
+		"/cfg/main.hcl": `locals {
+  module    = path.module
+  root      = path.root
+  cwd       = path.cwd
+  workspace = faketerraform.workspace
+  file      = file("${path.module}/x.json")
+}
+`,
+		"/cfg/x.json": `{}`,
+	})
+	c := NewBasicConfigFromArgs(NewBaseConfigArgs{
+		Basedir:         "/cfg",
+		DslFullName:     "faketerraform",
+		DslAbbreviation: "ft",
+		Sandbox:         &Sandbox{},
+	})
+	blocks, err := LoadHclBlocks(false, "/cfg")
+	require.NoError(s.T(), err)
+	require.NoError(s.T(), InitConfig(c, blocks))
+	cwd, err := os.Getwd()
+	require.NoError(s.T(), err)
+	locals := c.EvalContext().Variables["local"]
+	s.Equal(cty.StringVal("/cfg"), locals.GetAttr("module"))
+	s.Equal(cty.StringVal("/cfg"), locals.GetAttr("root"))
+	s.Equal(cty.StringVal(cwd), locals.GetAttr("cwd"))
+	s.Equal(cty.StringVal("default"), locals.GetAttr("workspace"))
+	s.Equal(cty.StringVal("{}"), locals.GetAttr("file"))
+	for _, l := range Blocks[*LocalBlock](c) {
+		ancestors, err := c.GetAncestors(l.Address())
+		require.NoError(s.T(), err)
+		s.Empty(ancestors)
+	}
+}
+
+func (s *builtinValuesSuite) TestPathModuleInChildModule() {
+	s.dummyFsWithFiles(map[string]string{
+		"/main.hcl": `module "child" {
+  source = "./modules/child"
+}
+`,
+		"/modules/child/main.hcl": `output "module" {
+  value = path.module
+}
+
+output "root" {
+  value = path.root
+}
+`,
+	})
+	config, err := BuildDummyConfig("/", "/", nil, nil)
+	require.NoError(s.T(), err)
+	_, err = RunDummyPlan(config)
+	require.NoError(s.T(), err)
+	s.Equal(cty.ObjectVal(map[string]cty.Value{
+		"module": cty.StringVal("/modules/child"),
+		"root":   cty.StringVal("/"),
+	}), Blocks[*ModuleBlock](config)[0].Value())
+}
+
+func (s *builtinValuesSuite) TestVariableDefaultReferencesBuiltinValues() {
+	s.dummyFsWithFiles(map[string]string{
+		"/cfg/main.hcl": `variable "content" {
+  default = file("${path.module}/x.json")
+}
+
+variable "workspace" {
+  default = faketerraform.workspace
+}
+
+`,
+		"/cfg/x.json": `{}`,
+	})
+	c := NewBasicConfigFromArgs(NewBaseConfigArgs{
+		Basedir:         "/cfg",
+		DslFullName:     "faketerraform",
+		DslAbbreviation: "ft",
+		Sandbox:         &Sandbox{},
+	})
+	blocks, err := LoadHclBlocks(false, "/cfg")
+	require.NoError(s.T(), err)
+	require.NoError(s.T(), InitConfig(c, blocks))
+	variables := make(map[string]*VariableBlock)
+	for _, v := range Blocks[*VariableBlock](c) {
+		variables[v.Name()] = v
+	}
+	s.Equal(cty.StringVal("{}"), variables["content"].Value())
+	s.Equal(cty.StringVal("default"), variables["workspace"].Value())
+}
+
+func (s *builtinValuesSuite) TestVariableDefaultCannotReferenceOtherBlocks() {
+	s.dummyFsWithFiles(map[string]string{
+		"/cfg/main.hcl": `variable "invalid" {
+  default = "${path.module}/${local.a}"
+}
+
+locals {
+  a = "a"
+}
+`,
+	})
+	c := NewBasicConfig("/cfg", "faketerraform", "ft", nil, nil, nil)
+	blocks, err := LoadHclBlocks(false, "/cfg")
+	require.NoError(s.T(), err)
+	s.ErrorContains(InitConfig(c, blocks), "default value of var.invalid cannot reference `local.a`")
+}
+
+func (s *builtinValuesSuite) TestBuiltinRootNames() {
+	s.Equal(map[string]struct{}{"path": {}}, builtinRootNames(nil))
+	c := NewBasicConfig("/", "faketerraform", "ft", nil, nil, nil)
+	s.Equal(map[string]struct{}{"path": {}, "faketerraform": {}}, builtinRootNames(c))
+}
@@ -0,0 +1,156 @@
+package golden
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+)
+
+var _ PlanBlock = &CheckBlock{}
+var _ CustomDecode = &CheckBlock{}
+
+type CheckStatus string
+
+const (
+	CheckStatusUnknown CheckStatus = "unknown"
+	CheckStatusPass    CheckStatus = "pass"
+	CheckStatusFail    CheckStatus = "fail"
+)
+
+type CheckAssert struct {
+	Condition    bool   `hcl:"condition"`
+	ErrorMessage string `hcl:"error_message"`
+}
+
+// CheckResult is the result of a `check` block, failed assertions are reported as warning diagnostics.
+type CheckResult struct {
+	Address     string
+	Status      CheckStatus
+	Diagnostics hcl.Diagnostics
+}
+
+// CheckBlock is a soft policy, its failed assertions are reported as warnings and never fail the plan.
+type CheckBlock struct {
+	*BaseBlock
+	result CheckResult
+}
+
+func (c *CheckBlock) Decode(block *HclBlock, context *hcl.EvalContext) error {
+	return nil
+}
+
+func (c *CheckBlock) ExecuteDuringPlan() error {
+	result := CheckResult{
+		Address: c.Address(),
+		Status:  CheckStatusPass,
+	}
+	ctx := c.EvalContext()
+	for _, nb := range c.HclBlock().NestedBlocks() {
+		if nb.Type != "assert" {
+			continue
+		}
+		var assert CheckAssert
+		diag := decodeBody(nb.Body, ctx, &assert)
+		if diag.HasErrors() {
+			result.Status = CheckStatusUnknown
+			for _, d := range diag {
+				result.Diagnostics = result.Diagnostics.Append(&hcl.Diagnostic{
+					Severity: hcl.DiagWarning,
+					Summary:  "check assertion cannot be evaluated",
+					Detail:   fmt.Sprintf("%s: %s", c.Address(), d.Error()),
+					Subject:  d.Subject,
+				})
+			}
+			continue
+		}
+		if assert.Condition {
+			continue
+		}
+		if result.Status == CheckStatusPass {
+			result.Status = CheckStatusFail
+		}
+		subject := nb.Body.Range().Ptr()
+		if attr, ok := nb.Body.Attributes["condition"]; ok {
+			subject = attr.SrcRange.Ptr()
+		}
+		result.Diagnostics = result.Diagnostics.Append(&hcl.Diagnostic{
+			Severity: hcl.DiagWarning,
+			Summary:  "check failed",
+			Detail:   fmt.Sprintf("%s: %s", c.Address(), assert.ErrorMessage),
+			Subject:  subject,
+		})
+	}
+	c.result = result
+	return nil
+}
+
+// Result returns the result of the latest execution, the status is unknown if the check has not been executed.
+func (c *CheckBlock) Result() CheckResult {
+	if c.result.Address == "" {
+		return CheckResult{
+			Address: c.Address(),
+			Status:  CheckStatusUnknown,
+		}
+	}
+	return c.result
+}
+
+func (c *CheckBlock) Type() string {
+	return ""
+}
+
+func (c *CheckBlock) BlockType() string {
+	return "check"
+}
+
+func (c *CheckBlock) AddressLength() int {
+	return 2
+}
+
+func (c *CheckBlock) CanExecutePrePlan() bool {
+	return false
+}
+
+// CheckResults returns results of all `check` blocks ordered by address.
+func (c *BaseConfig) CheckResults() []CheckResult {
+	var results []CheckResult
+	for _, cb := range Blocks[*CheckBlock](c) {
+		results = append(results, cb.Result())
+	}
+	sort.Slice(results, func(i, j int) bool {
+		return results[i].Address < results[j].Address
+	})
+	return results
+}
+
+// CheckDiagnostics returns warning diagnostics of all `check` blocks.
+func (c *BaseConfig) CheckDiagnostics() hcl.Diagnostics {
+	var diags hcl.Diagnostics
+	for _, r := range c.CheckResults() {
+		diags = diags.Extend(r.Diagnostics)
+	}
+	return diags
+}
+
+func (c *BaseConfig) CheckSummary() string {
+	results := c.CheckResults()
+	counts := make(map[CheckStatus]int)
+	for _, r := range results {
+		counts[r.Status]++
+	}
+	sb := strings.Builder{}
+	fmt.Fprintf(&sb, "Checks: %d passed, %d failed, %d unknown", counts[CheckStatusPass], counts[CheckStatusFail], counts[CheckStatusUnknown])
+	for _, r := range results {
+		fmt.Fprintf(&sb, "\n  %s: %s", r.Address, r.Status)
+		for _, d := range r.Diagnostics {
+			location := ""
+			if d.Subject != nil {
+				location = d.Subject.String() + ": "
+			}
+			fmt.Fprintf(&sb, "\n    - %s%s", location, d.Detail)
+		}
+	}
+	return sb.String()
+}
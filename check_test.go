@@ -0,0 +1,142 @@
+package golden
+
+import (
+	"testing"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type checkSuite struct {
+	suite.Suite
+	*testBase
+}
+
+func TestCheckSuite(t *testing.T) {
+	suite.Run(t, new(checkSuite))
+}
+
+func (s *checkSuite) SetupTest() {
+	s.testBase = newTestBase()
+}
+
+func (s *checkSuite) TearDownTest() {
+	s.teardown()
+}
+
+func (s *checkSuite) TestCheck_FailedAssertShouldWarnWithoutFailingPlan() {
+	content := `data "dummy" foo {
+  data = {
+    key = "value"
+  }
+}
+
+check "key" {
+  assert {
+    condition     = data.dummy.foo.data["key"] == "other"
+    error_message = "key must be other"
+  }
+  assert {
+    condition     = data.dummy.foo.attribute == "default_value"
+    error_message = "attribute must be default_value"
+  }
+}
+
+check "passed" {
+  assert {
+    condition     = true
+    error_message = "never"
+  }
+}
+`
+	s.dummyFsWithFiles(map[string]string{
+		"test.hcl": content,
+	})
+	config, err := BuildDummyConfig("", "", nil, nil)
+	require.NoError(s.T(), err)
+	_, err = RunDummyPlan(config)
+	require.NoError(s.T(), err)
+	c := config.(*DummyConfig)
+	results := c.CheckResults()
+	require.Len(s.T(), results, 2)
+	s.Equal("check.key", results[0].Address)
+	s.Equal(CheckStatusFail, results[0].Status)
+	require.Len(s.T(), results[0].Diagnostics, 1)
+	d := results[0].Diagnostics[0]
+	s.Equal(hcl.DiagWarning, d.Severity)
+	s.Equal("check.key: key must be other", d.Detail)
+	s.Equal(9, d.Subject.Start.Line)
+	s.Equal(CheckStatusPass, results[1].Status)
+	s.False(c.CheckDiagnostics().HasErrors())
+	summary := c.CheckSummary()
+	s.Contains(summary, "Checks: 1 passed, 1 failed, 0 unknown")
+	s.Contains(summary, "test.hcl:9,5-58: check.key: key must be other")
+}
+
+func (s *checkSuite) TestCheck_ShouldRunAfterReferencedBlocks() {
+	content := `data "dummy" foo {
+  data = {
+    key = "value"
+  }
+}
+
+check "key" {
+  assert {
+    condition     = data.dummy.foo.data["key"] == "value"
+    error_message = "key must be value"
+  }
+}
+`
+	s.dummyFsWithFiles(map[string]string{
+		"test.hcl": content,
+	})
+	config, err := BuildDummyConfig("", "", nil, nil)
+	require.NoError(s.T(), err)
+	_, err = RunDummyPlan(config)
+	require.NoError(s.T(), err)
+	results := config.(*DummyConfig).CheckResults()
+	require.Len(s.T(), results, 1)
+	s.Equal(CheckStatusPass, results[0].Status)
+	s.Empty(results[0].Diagnostics)
+}
+
+func (s *checkSuite) TestCheck_EvaluationErrorShouldBeWarning() {
+	content := `check "bad" {
+  assert {
+    condition     = "not_a_bool"
+    error_message = "bad"
+  }
+}
+`
+	s.dummyFsWithFiles(map[string]string{
+		"test.hcl": content,
+	})
+	config, err := BuildDummyConfig("", "", nil, nil)
+	require.NoError(s.T(), err)
+	_, err = RunDummyPlan(config)
+	require.NoError(s.T(), err)
+	results := config.(*DummyConfig).CheckResults()
+	require.Len(s.T(), results, 1)
+	s.Equal(CheckStatusUnknown, results[0].Status)
+	require.NotEmpty(s.T(), results[0].Diagnostics)
+	s.Equal(hcl.DiagWarning, results[0].Diagnostics[0].Severity)
+}
+
+func (s *checkSuite) TestCheck_NotExecutedCheckShouldBeUnknown() {
+	content := `check "key" {
+  assert {
+    condition     = true
+    error_message = "never"
+  }
+}
+`
+	s.dummyFsWithFiles(map[string]string{
+		"test.hcl": content,
+	})
+	config, err := BuildDummyConfig("", "", nil, nil)
+	require.NoError(s.T(), err)
+	results := config.(*DummyConfig).CheckResults()
+	require.Len(s.T(), results, 1)
+	s.Equal(CheckStatusUnknown, results[0].Status)
+}
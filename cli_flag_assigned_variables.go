@@ -38,7 +38,9 @@ func (v CliFlagAssignedVariable) Variables(c *BaseConfig) (map[string]VariableVa
 		}
 		return nil, fmt.Errorf(`a variable named "%s" was assigned on the command line, but cannot find a variable of that name. To use this value, add a "variable" block to the configuraion`, v.varName)
 	}
-	read := vb.parseVariableValueFromString(v.rawValue, false)
+	read := vb.parseVariableValueFromString(v.rawValue, false).withSource(VariableValueSource{
+		Type: VariableValueSourceCliFlag,
+	})
 	return map[string]VariableValueRead{
 		read.Name: read,
 	}, nil
@@ -64,5 +66,9 @@ func (v CliFlagAssignedVariableFile) Variables(c *BaseConfig) (map[string]Variab
 	if !exist && !strings.HasPrefix(v.varFileName, c.variableConfigFilesDir()) {
 		return CliFlagAssignedVariableFile{varFileName: filepath.Join(c.variableConfigFilesDir(), v.varFileName)}.Variables(c)
 	}
-	return c.readVariablesFromVarFile(v.varFileName)
+	reads, err := c.readVariablesFromVarFile(v.varFileName)
+	if err != nil {
+		return nil, err
+	}
+	return withSourceType(reads, VariableValueSourceCliFlagVarFile), nil
 }
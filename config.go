@@ -22,6 +22,7 @@ type Config interface {
 	EvalContext() *hcl.EvalContext
 	RunPrePlan() error
 	RunPlan() error
+	RunApply() error
 	ValidBlockAddress(address string) bool
 	DslFullName() string
 	DslAbbreviation() string
@@ -43,6 +44,14 @@ func Blocks[T Block](c directedAcyclicGraph) []T {
 func InitConfig(config Config, hclBlocks []*HclBlock) error {
 	var err error
 
+	if bc, ok := config.(interface{ baseConfig() *BaseConfig }); ok {
+		if err = bc.baseConfig().checkOverrideFunctions(); err != nil {
+			return err
+		}
+		if err = bc.baseConfig().resolveWorkspacePaths(); err != nil {
+			return err
+		}
+	}
 	var blocks []Block
 	for _, hb := range hclBlocks {
 		b, wrapError := wrapBlock(config, hb)
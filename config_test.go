@@ -2,15 +2,8 @@ package golden
 
 import (
 	"context"
-	"fmt"
-	"path/filepath"
 	"testing"
 
-	"github.com/hashicorp/go-multierror"
-	"github.com/hashicorp/hcl/v2"
-	"github.com/hashicorp/hcl/v2/hclsyntax"
-	"github.com/hashicorp/hcl/v2/hclwrite"
-	"github.com/spf13/afero"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"github.com/stretchr/testify/suite"
@@ -44,52 +37,7 @@ func BuildDummyConfig(baseDir, cfgDir string, cliFlagAssignedVariables []CliFlag
 }
 
 func loadHclBlocks(ignoreUnsupportedBlock bool, dir string) ([]*HclBlock, error) {
-	fs := testFsFactory()
-	matches, err := afero.Glob(fs, filepath.Join(dir, "*.hcl"))
-	if err != nil {
-		return nil, err
-	}
-	if len(matches) == 0 {
-		return nil, fmt.Errorf("no `.hcl` file found at %s", dir)
-	}
-
-	var blocks []*HclBlock
-
-	for _, filename := range matches {
-		content, fsErr := afero.ReadFile(fs, filename)
-		if fsErr != nil {
-			err = multierror.Append(err, fsErr)
-			continue
-		}
-		readFile, diag := hclsyntax.ParseConfig(content, filename, hcl.InitialPos)
-		if diag.HasErrors() {
-			err = multierror.Append(err, diag.Errs()...)
-			continue
-		}
-		writeFile, diag := hclwrite.ParseConfig(content, filename, hcl.InitialPos)
-		if diag.HasErrors() {
-			err = multierror.Append(err, diag.Errs()...)
-			continue
-		}
-		blocks = append(blocks, AsHclBlocks(readFile.Body.(*hclsyntax.Body).Blocks, writeFile.Body().Blocks())...)
-	}
-	if err != nil {
-		return nil, err
-	}
-
-	var r []*HclBlock
-
-	// First loop: parse all rule blocks
-	for _, b := range blocks {
-		if IsBlockTypeWanted(b.Type) {
-			r = append(r, b)
-			continue
-		}
-		if !ignoreUnsupportedBlock {
-			err = multierror.Append(err, fmt.Errorf("invalid block type: %s %s", b.Type, b.Range().String()))
-		}
-	}
-	return r, err
+	return loadHclBlocksFromFs(testFsFactory(), ignoreUnsupportedBlock, dir)
 }
 
 func RunDummyPlan(c Config) (*DummyPlan, error) {
@@ -0,0 +1,95 @@
+package golden
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/zclconf/go-cty/cty"
+)
+
+const consolePrompt = "> "
+
+// EvalResult is the value of an expression evaluated by Eval.
+type EvalResult struct {
+	Value cty.Value
+}
+
+func (r EvalResult) Type() cty.Type {
+	return r.Value.Type()
+}
+
+// TypeString returns the type in type constraint syntax, like `list(string)`.
+func (r EvalResult) TypeString() string {
+	return typeString(r.Type())
+}
+
+// String returns the value in HCL syntax, unknown values are rendered as `(known after apply)` and sensitive values as `(sensitive value)`.
+func (r EvalResult) String() string {
+	if r.Value.ContainsMarked() {
+		return redactedValue
+	}
+	if !r.Value.IsWhollyKnown() {
+		return "(known after apply)"
+	}
+	return strings.TrimSpace(string(hclwrite.Format(hclwrite.TokensForValue(r.Value).Bytes())))
+}
+
+// Eval evaluates an HCL expression against EvalContext, blocks are known after RunPrePlan or RunPlan.
+func (c *BaseConfig) Eval(expr string) (EvalResult, error) {
+	e, diag := hclsyntax.ParseExpression([]byte(expr), "<console>", hcl.InitialPos)
+	if diag.HasErrors() {
+		return EvalResult{}, diag
+	}
+	value, diag := e.Value(c.EvalContext())
+	if diag.HasErrors() {
+		return EvalResult{}, diag
+	}
+	return EvalResult{Value: value}, nil
+}
+
+// RunConsole reads an expression per line from in and writes results to out, until `exit` or the end of in.
+// Evaluation errors are written to out and don't stop the console.
+func (c *BaseConfig) RunConsole(in io.Reader, out io.Writer) error {
+	scanner := bufio.NewScanner(in)
+	for {
+		if _, err := fmt.Fprint(out, consolePrompt); err != nil {
+			return err
+		}
+		if !scanner.Scan() {
+			_, err := fmt.Fprintln(out)
+			if err != nil {
+				return err
+			}
+			return scanner.Err()
+		}
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		if line == "exit" {
+			return nil
+		}
+		if _, err := fmt.Fprintln(out, c.consoleOutput(line)); err != nil {
+			return err
+		}
+	}
+}
+
+// consoleOutput returns the result or the error of a console line, panics while evaluating or rendering are reported as errors too.
+func (c *BaseConfig) consoleOutput(line string) (output string) {
+	defer func() {
+		if r := recover(); r != nil {
+			output = fmt.Sprintf("Error: %v", r)
+		}
+	}()
+	result, err := c.Eval(line)
+	if err != nil {
+		return fmt.Sprintf("Error: %s", err.Error())
+	}
+	return result.String()
+}
@@ -0,0 +1,156 @@
+package golden
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"github.com/zclconf/go-cty/cty"
+	"github.com/zclconf/go-cty/cty/function"
+)
+
+type consoleSuite struct {
+	suite.Suite
+	*testBase
+}
+
+func TestConsoleSuite(t *testing.T) {
+	suite.Run(t, new(consoleSuite))
+}
+
+func (s *consoleSuite) SetupTest() {
+	s.testBase = newTestBase()
+}
+
+func (s *consoleSuite) TearDownTest() {
+	s.teardown()
+}
+
+func (s *consoleSuite) config() *DummyConfig {
+	s.dummyFsWithFiles(map[string]string{
+		"/main.hcl": `variable "regions" {
+  type    = list(string)
+  default = ["eastus", "westus"]
+}
+
+locals {
+  x = {
+    name    = "golden"
+    regions = var.regions
+  }
+}
+
+data "dummy" "foo" {
+  data = {
+    key = local.x.name
+  }
+}
+`,
+	})
+	c, err := BuildDummyConfig("/", "/", nil, nil)
+	require.NoError(s.T(), err)
+	return c.(*DummyConfig)
+}
+
+func (s *consoleSuite) TestEval_AfterPrePlan() {
+	c := s.config()
+	result, err := c.Eval(`local.x.regions`)
+	require.NoError(s.T(), err)
+	s.Equal(cty.ListVal([]cty.Value{cty.StringVal("eastus"), cty.StringVal("westus")}), result.Value)
+	s.Equal("list(string)", result.TypeString())
+	s.Equal(`["eastus", "westus"]`, result.String())
+
+	result, err = c.Eval(`upper(local.x.name)`)
+	require.NoError(s.T(), err)
+	s.Equal(`"GOLDEN"`, result.String())
+	s.Equal(cty.String, result.Type())
+}
+
+func (s *consoleSuite) TestEval_AfterPlan() {
+	c := s.config()
+	require.NoError(s.T(), c.RunPlan())
+	result, err := c.Eval(`data.dummy.foo.data`)
+	require.NoError(s.T(), err)
+	s.Equal("map(string)", result.TypeString())
+	s.Equal(`{
+  key = "golden"
+}`, result.String())
+}
+
+func (s *consoleSuite) TestEval_Errors() {
+	c := s.config()
+	_, err := c.Eval(`local.x.`)
+	s.NotNil(err)
+	_, err = c.Eval(`local.y`)
+	s.ErrorContains(err, "Unsupported attribute")
+}
+
+func (s *consoleSuite) TestRunConsole() {
+	c := s.config()
+	out := new(bytes.Buffer)
+	in := strings.NewReader("local.x.name\n\nlocal.y\nlength(var.regions)\nexit\nlocal.x.name\n")
+	require.NoError(s.T(), c.RunConsole(in, out))
+	s.True(strings.HasPrefix(out.String(), "> \"golden\"\n> > Error: <console>:1,6-8: Unsupported attribute;"), out.String())
+	s.True(strings.HasSuffix(out.String(), "> 2\n> "), out.String())
+}
+
+func (s *consoleSuite) TestRunConsole_EndOfInput() {
+	c := s.config()
+	out := new(bytes.Buffer)
+	require.NoError(s.T(), c.RunConsole(strings.NewReader("1 + 1"), out))
+	s.Equal("> 2\n> \n", out.String())
+}
+
+func (s *consoleSuite) TestEval_SensitiveValue() {
+	c := s.config()
+	result, err := c.Eval(`sensitive("p@ssw0rd")`)
+	require.NoError(s.T(), err)
+	s.Equal("(sensitive value)", result.String())
+	s.Equal("string", result.TypeString())
+	result, err = c.Eval(`{ name = local.x.name, password = sensitive("p@ssw0rd") }`)
+	require.NoError(s.T(), err)
+	s.Equal("(sensitive value)", result.String())
+
+	out := new(bytes.Buffer)
+	require.NoError(s.T(), c.RunConsole(strings.NewReader("sensitive(\"p@ssw0rd\")\nlocal.x.name\n"), out))
+	s.Equal("> (sensitive value)\n> \"golden\"\n> \n", out.String())
+}
+
+func (s *consoleSuite) TestRunConsole_RecoverFromPanic() {
+	c := s.config()
+	opaque := cty.Capsule("opaque", reflect.TypeOf(0))
+	require.NoError(s.T(), c.RegisterFunction("test", "opaque", function.New(&function.Spec{
+		Type: function.StaticReturnType(opaque),
+		Impl: func(args []cty.Value, retType cty.Type) (cty.Value, error) {
+			i := 1
+			return cty.CapsuleVal(opaque, &i), nil
+		},
+	})))
+	result, err := c.Eval(`test::opaque()`)
+	require.NoError(s.T(), err)
+	s.Equal(opaque.FriendlyName(), result.TypeString())
+
+	out := new(bytes.Buffer)
+	require.NoError(s.T(), c.RunConsole(strings.NewReader("test::opaque()\nlocal.x.name\n"), out))
+	s.True(strings.HasPrefix(out.String(), "> Error: "), out.String())
+	s.True(strings.HasSuffix(out.String(), "> \"golden\"\n> \n"), out.String())
+}
+
+func (s *consoleSuite) TestRunConsole_SensitiveVariable() {
+	s.dummyFsWithFiles(map[string]string{
+		"/main.hcl": `variable "password" {
+  type      = string
+  default   = "hunter2"
+  sensitive = true
+}
+`,
+	})
+	c, err := BuildDummyConfig("/", "/", nil, nil)
+	require.NoError(s.T(), err)
+	out := new(bytes.Buffer)
+	require.NoError(s.T(), c.(*DummyConfig).RunConsole(strings.NewReader("var.password\n{ password = var.password }\nlength(var.password)\n"), out))
+	s.Equal("> (sensitive value)\n> (sensitive value)\n> (sensitive value)\n> \n", out.String())
+}
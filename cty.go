@@ -6,6 +6,7 @@ import (
 	"strings"
 
 	"github.com/zclconf/go-cty/cty"
+	"github.com/zclconf/go-cty/cty/gocty"
 )
 
 var customTypeMapping = make(map[reflect.Type]cty.Type)
@@ -74,6 +75,88 @@ func ToCtyValue(input any) cty.Value {
 	}
 }
 
+// FromCtyValue is the reverse of ToCtyValue, it sets the value that target points to from val.
+// Struct fields are matched by their `hcl` or `attribute` tag names, like ToCtyValue does.
+func FromCtyValue(val cty.Value, target any) error {
+	v := reflect.ValueOf(target)
+	if v.Kind() != reflect.Ptr || v.IsNil() {
+		return fmt.Errorf("target must be a non-nil pointer, got %T", target)
+	}
+	return fromCtyValue(val, v.Elem())
+}
+
+func fromCtyValue(val cty.Value, dst reflect.Value) error {
+	if dst.Type() == reflect.TypeOf(cty.Value{}) {
+		dst.Set(reflect.ValueOf(val))
+		return nil
+	}
+	// empty collections are restored as nil, like what gohcl decodes from omitted attributes and blocks.
+	emptyCollection := (dst.Kind() == reflect.Slice || dst.Kind() == reflect.Map) && val.IsKnown() && !val.IsNull() && val.CanIterateElements() && val.LengthInt() == 0
+	if val == cty.NilVal || val.IsNull() || emptyCollection {
+		dst.Set(reflect.Zero(dst.Type()))
+		return nil
+	}
+	if !val.IsWhollyKnown() {
+		return fmt.Errorf("value must be known")
+	}
+	switch dst.Kind() {
+	case reflect.Ptr:
+		nv := reflect.New(dst.Type().Elem())
+		if err := fromCtyValue(val, nv.Elem()); err != nil {
+			return err
+		}
+		dst.Set(nv)
+		return nil
+	case reflect.Slice:
+		if !val.CanIterateElements() {
+			return fmt.Errorf("%s cannot be converted to %s", val.Type().FriendlyName(), dst.Type())
+		}
+		r := reflect.MakeSlice(dst.Type(), 0, val.LengthInt())
+		for it := val.ElementIterator(); it.Next(); {
+			k, ev := it.Element()
+			elem := reflect.New(dst.Type().Elem()).Elem()
+			if err := fromCtyValue(ev, elem); err != nil {
+				return fmt.Errorf("[%s]: %+v", CtyValueToString(k), err)
+			}
+			r = reflect.Append(r, elem)
+		}
+		dst.Set(r)
+		return nil
+	case reflect.Map:
+		if !val.Type().IsMapType() && !val.Type().IsObjectType() {
+			return fmt.Errorf("%s cannot be converted to %s", val.Type().FriendlyName(), dst.Type())
+		}
+		r := reflect.MakeMapWithSize(dst.Type(), val.LengthInt())
+		for it := val.ElementIterator(); it.Next(); {
+			k, ev := it.Element()
+			elem := reflect.New(dst.Type().Elem()).Elem()
+			if err := fromCtyValue(ev, elem); err != nil {
+				return fmt.Errorf("[%s]: %+v", CtyValueToString(k), err)
+			}
+			r.SetMapIndex(reflect.ValueOf(k.AsString()).Convert(dst.Type().Key()), elem)
+		}
+		dst.Set(r)
+		return nil
+	case reflect.Struct:
+		if !val.Type().IsObjectType() {
+			return fmt.Errorf("%s cannot be converted to %s", val.Type().FriendlyName(), dst.Type())
+		}
+		for i := 0; i < dst.NumField(); i++ {
+			field := dst.Field(i)
+			name, _ := fieldName(dst.Type().Field(i))
+			if !field.CanSet() || !val.Type().HasAttribute(name) {
+				continue
+			}
+			if err := fromCtyValue(val.GetAttr(name), field); err != nil {
+				return fmt.Errorf("%s: %+v", name, err)
+			}
+		}
+		return nil
+	default:
+		return gocty.FromCtyValue(val, dst.Addr().Interface())
+	}
+}
+
 func GoTypeToCtyType(goType reflect.Type) cty.Type {
 	if goType == nil {
 		return cty.NilType
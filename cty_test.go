@@ -155,6 +155,60 @@ func TestToCtyValue(t *testing.T) {
 	}
 }
 
+func TestFromCtyValue_RoundTrip(t *testing.T) {
+	tests := []struct {
+		name  string
+		input any
+	}{
+		{
+			name:  "int",
+			input: 10,
+		},
+		{
+			name:  "slice of string",
+			input: []string{"a", "b"},
+		},
+		{
+			name:  "map",
+			input: map[string]int{"a": 1},
+		},
+		{
+			name: "nested struct pointers",
+			input: KubernetesCluster{
+				Name:                      "k8s",
+				ImageCleanerIntervalHours: 48,
+				DefaultNodePool: &KubernetesClusterNodePool{
+					Name:      "default",
+					NodeCount: 3,
+					LinuxOsConfig: &LinuxOsConfig{
+						SwapFileSizeMb: 1024,
+					},
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			target := reflect.New(reflect.TypeOf(tt.input))
+			err := FromCtyValue(ToCtyValue(tt.input), target.Interface())
+			assert.NoError(t, err)
+			assert.Equal(t, tt.input, target.Elem().Interface())
+		})
+	}
+}
+
+func TestFromCtyValue_Errors(t *testing.T) {
+	var s string
+	assert.Error(t, FromCtyValue(cty.StringVal("a"), s))
+	var i int
+	assert.Error(t, FromCtyValue(cty.UnknownVal(cty.Number), &i))
+	var c KubernetesCluster
+	err := FromCtyValue(cty.ObjectVal(map[string]cty.Value{
+		"image_cleaner_interval_hours": cty.StringVal("not a number"),
+	}), &c)
+	assert.ErrorContains(t, err, "image_cleaner_interval_hours")
+}
+
 func TestGoTypeToCtyType(t *testing.T) {
 	tests := []struct {
 		name        string
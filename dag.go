@@ -27,7 +27,7 @@ func (d *Dag) buildDag(blocks []Block) error {
 		}
 	}
 	for _, b := range blocks {
-		diag := hclsyntax.Walk(b.HclBlock().Body, newDagWalker(d, b.Address()))
+		diag := hclsyntax.Walk(b.HclBlock().Body, newDagWalker(d, b.Address(), builtinRootNames(b.Config()), configLogger(b.Config())))
 		if diag.HasErrors() {
 			walkErr = multierror.Append(walkErr, diag.Errs()...)
 		}
@@ -61,6 +61,18 @@ func (d *Dag) runDag(c Config, onReady func(Block) error) error {
 	for _, b := range otherBlocks {
 		pending.Enqueue(b)
 	}
+	executed := make(map[string]struct{})
+	// blocks left behind are skipped only when some block has failed, RunPrePlan doesn't execute other blocks.
+	defer func() {
+		if err == nil {
+			return
+		}
+		for _, address := range sortedKeys(d.GetVertices()) {
+			if _, ok := executed[address]; !ok {
+				notifyHooks(c, func(h Hook) { h.BlockSkipped(moduleAddress(c, address)) })
+			}
+		}
+	}()
 	for !pending.Empty() {
 		next, _ := pending.Dequeue()
 		b := next.(Block)
@@ -88,6 +100,7 @@ func (d *Dag) runDag(c Config, onReady func(Block) error) error {
 		if !ready {
 			continue
 		}
+		notifyHooks(c, func(h Hook) { h.BlockQueued(moduleAddress(c, address)) })
 		if b.expandable() {
 			children, dagErr := d.GetChildren(address)
 			if dagErr != nil {
@@ -97,6 +110,11 @@ func (d *Dag) runDag(c Config, onReady func(Block) error) error {
 			if err != nil {
 				return err
 			}
+			var instances []string
+			for _, eb := range expandedBlocks {
+				instances = append(instances, FullAddress(eb))
+			}
+			notifyHooks(c, func(h Hook) { h.BlockExpanded(moduleAddress(c, address), instances) })
 			newPending := linkedlistqueue.New()
 			for _, eb := range expandedBlocks {
 				newPending.Enqueue(eb)
@@ -110,6 +128,7 @@ func (d *Dag) runDag(c Config, onReady func(Block) error) error {
 			pending = newPending
 			continue
 		}
+		executed[address] = struct{}{}
 		if callbackErr := onReady(b); callbackErr != nil {
 			err = multierror.Append(err, callbackErr)
 		}
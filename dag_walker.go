@@ -1,8 +1,13 @@
 package golden
 
 import (
+	"errors"
+	"fmt"
+	"log/slog"
+
 	"github.com/hashicorp/hcl/v2"
 	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/heimdalr/dag"
 )
 
 var _ hclsyntax.Walker = dagWalker{}
@@ -10,12 +15,17 @@ var _ hclsyntax.Walker = dagWalker{}
 type dagWalker struct {
 	dag          *Dag
 	startAddress string
+	// ignoredRoots are roots of traversals that don't reference blocks, like `path.module`.
+	ignoredRoots map[string]struct{}
+	logger       *slog.Logger
 }
 
-func newDagWalker(d *Dag, startAddress string) dagWalker {
+func newDagWalker(d *Dag, startAddress string, ignoredRoots map[string]struct{}, logger *slog.Logger) dagWalker {
 	return dagWalker{
 		dag:          d,
 		startAddress: startAddress,
+		ignoredRoots: ignoredRoots,
+		logger:       logger,
 	}
 }
 
@@ -24,6 +34,9 @@ func (d dagWalker) Enter(node hclsyntax.Node) hcl.Diagnostics {
 	if expr, ok := node.(hclsyntax.Expression); ok {
 		traversals := expr.Variables()
 		for _, traversal := range traversals {
+			if _, ignored := d.ignoredRoots[traversal.RootName()]; ignored {
+				continue
+			}
 			for i, traverser := range traversal {
 				name := name(traverser)
 				refIter, ok := refIters[name]
@@ -42,13 +55,24 @@ func (d dagWalker) Enter(node hclsyntax.Node) hcl.Diagnostics {
 
 					if _, edgeExist := dests[dest]; !edgeExist {
 						err := d.dag.addEdge(src, dest)
-						if err != nil {
+						if err == nil {
+							d.logger.Debug("added dag edge", "from", src, "to", dest, "range", expr.Range().String())
+							continue
+						}
+						if errors.As(err, &dag.EdgeLoopError{}) {
 							diag = diag.Append(&hcl.Diagnostic{
 								Severity: hcl.DiagError,
-								Summary:  "cannot add edge",
-								Detail:   err.Error(),
+								Summary:  "dependency cycle",
+								Detail:   fmt.Sprintf("%s references %s, but %s depends on %s already: %s", dest, src, src, dest, err.Error()),
+								Subject:  expr.Range().Ptr(),
 							})
+							continue
 						}
+						diag = diag.Append(&hcl.Diagnostic{
+							Severity: hcl.DiagError,
+							Summary:  "cannot add edge",
+							Detail:   err.Error(),
+						})
 					}
 				}
 			}
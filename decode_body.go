@@ -0,0 +1,73 @@
+package golden
+
+import (
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/gohcl"
+	"github.com/zclconf/go-cty/cty"
+)
+
+// decodeBody is gohcl.DecodeBody for bodies that could reference sensitive values, gohcl panics when it decodes a marked value into a Go field.
+// Marks are removed before decoding, so sensitive values decoded into block fields are not redacted anymore.
+func decodeBody(body hcl.Body, ctx *hcl.EvalContext, val any) hcl.Diagnostics {
+	return gohcl.DecodeBody(unmarkedBody{Body: body}, ctx, val)
+}
+
+type unmarkedBody struct {
+	hcl.Body
+}
+
+func (b unmarkedBody) Content(schema *hcl.BodySchema) (*hcl.BodyContent, hcl.Diagnostics) {
+	content, diags := b.Body.Content(schema)
+	return unmarkedContent(content), diags
+}
+
+func (b unmarkedBody) PartialContent(schema *hcl.BodySchema) (*hcl.BodyContent, hcl.Body, hcl.Diagnostics) {
+	content, remain, diags := b.Body.PartialContent(schema)
+	if remain != nil {
+		remain = unmarkedBody{Body: remain}
+	}
+	return unmarkedContent(content), remain, diags
+}
+
+func (b unmarkedBody) JustAttributes() (hcl.Attributes, hcl.Diagnostics) {
+	attrs, diags := b.Body.JustAttributes()
+	return unmarkedAttributes(attrs), diags
+}
+
+func unmarkedContent(content *hcl.BodyContent) *hcl.BodyContent {
+	if content == nil {
+		return nil
+	}
+	r := *content
+	r.Attributes = unmarkedAttributes(content.Attributes)
+	r.Blocks = nil
+	for _, b := range content.Blocks {
+		nb := *b
+		nb.Body = unmarkedBody{Body: b.Body}
+		r.Blocks = append(r.Blocks, &nb)
+	}
+	return &r
+}
+
+func unmarkedAttributes(attrs hcl.Attributes) hcl.Attributes {
+	if attrs == nil {
+		return nil
+	}
+	r := make(hcl.Attributes, len(attrs))
+	for n, a := range attrs {
+		na := *a
+		na.Expr = unmarkedExpression{Expression: a.Expr}
+		r[n] = &na
+	}
+	return r
+}
+
+type unmarkedExpression struct {
+	hcl.Expression
+}
+
+func (e unmarkedExpression) Value(ctx *hcl.EvalContext) (cty.Value, hcl.Diagnostics) {
+	v, diags := e.Expression.Value(ctx)
+	v, _ = v.UnmarkDeep()
+	return v, diags
+}
@@ -0,0 +1,113 @@
+package golden
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+
+	"github.com/hashicorp/go-multierror"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/zclconf/go-cty/cty"
+)
+
+// DestroyBlock is an ApplyBlock that cleans up what it applied once it's removed from configuration.
+// prior is an object of the values recorded in state by the last apply.
+type DestroyBlock interface {
+	ApplyBlock
+	Destroy(prior cty.Value) error
+}
+
+// destroyRemovedBlocks destroys blocks recorded in state but missing from configuration, including child configs of modules, in reverse dependency order.
+// Blocks with `create_before_destroy` are destroyed after apply, others before apply.
+// Blocks that don't implement DestroyBlock are just forgotten, a block stays in state if it or any block depending on it failed to destroy.
+func (c *BaseConfig) destroyRemovedBlocks(s *State, createBeforeDestroy bool) error {
+	pending := make(map[string]StateBlock)
+	for address, sb := range s.Blocks {
+		if !c.configured(address) && sb.CreateBeforeDestroy == createBeforeDestroy {
+			pending[address] = sb
+		}
+	}
+	failed := make(map[string]StateBlock)
+	var err error
+	for {
+		next := nextToDestroy(pending, failed)
+		if len(next) == 0 {
+			return err
+		}
+		for _, address := range next {
+			sb := pending[address]
+			delete(pending, address)
+			if destroyErr := c.destroy(address, sb); destroyErr != nil {
+				failed[address] = sb
+				err = multierror.Append(err, destroyErr)
+				continue
+			}
+			delete(s.Blocks, address)
+		}
+	}
+}
+
+// nextToDestroy returns pending addresses that no pending or failed block depends on.
+func nextToDestroy(pending, failed map[string]StateBlock) []string {
+	var r []string
+	for address := range pending {
+		if !dependedBy(address, pending) && !dependedBy(address, failed) {
+			r = append(r, address)
+		}
+	}
+	sort.Strings(r)
+	return r
+}
+
+func dependedBy(address string, blocks map[string]StateBlock) bool {
+	for _, sb := range blocks {
+		if slices.Contains(sb.DependsOn, address) {
+			return true
+		}
+	}
+	return false
+}
+
+// destroyForReplace destroys the prior instance of b, `lifecycle` of current configuration applies.
+func (c *BaseConfig) destroyForReplace(b Block, lc *Lifecycle, s *State) error {
+	sb, ok := s.Blocks[b.Address()]
+	if !ok {
+		return nil
+	}
+	sb.PreventDestroy = lc.PreventDestroy
+	if err := c.destroy(b.Address(), sb); err != nil {
+		return err
+	}
+	delete(s.Blocks, b.Address())
+	return nil
+}
+
+func (c *BaseConfig) destroy(address string, sb StateBlock) error {
+	if sb.PreventDestroy {
+		return fmt.Errorf("%s has `lifecycle.prevent_destroy` set and cannot be destroyed", address)
+	}
+	hb := NewHclBlock(&hclsyntax.Block{
+		Type:   sb.BlockType,
+		Labels: sb.Labels,
+		Body:   &hclsyntax.Body{},
+	}, nil, nil)
+	if sb.ForEachKey != nil {
+		hb.ForEach = NewForEach(cty.StringVal(*sb.ForEachKey), cty.NilVal)
+	}
+	b, err := wrapBlock(c, hb)
+	if err != nil {
+		return fmt.Errorf("cannot destroy %s: %+v", address, err)
+	}
+	db, ok := b.(DestroyBlock)
+	if !ok {
+		return nil
+	}
+	prior := make(map[string]cty.Value)
+	for n, v := range sb.Values {
+		prior[n] = v.Value
+	}
+	if err = db.Destroy(cty.ObjectVal(prior)); err != nil {
+		return fmt.Errorf("%s destroy error: %+v", address, err)
+	}
+	return nil
+}
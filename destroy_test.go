@@ -0,0 +1,198 @@
+package golden
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"github.com/zclconf/go-cty/cty"
+)
+
+var _ DestroyBlock = &DestroyableResource{}
+
+var destroyed []string
+var failDestroy = map[string]bool{}
+
+// destroyEvents records applies and destroys of DestroyableResource in order.
+var destroyEvents []string
+
+type DestroyableResource struct {
+	*BaseBlock
+	*BaseResource
+	Tags map[string]string `hcl:"tags,optional"`
+}
+
+func (d *DestroyableResource) Type() string {
+	return "destroyable"
+}
+
+func (d *DestroyableResource) ExecuteDuringPlan() error {
+	return nil
+}
+
+func (d *DestroyableResource) Apply() error {
+	destroyEvents = append(destroyEvents, "apply "+d.Tags["name"])
+	return nil
+}
+
+func (d *DestroyableResource) Destroy(prior cty.Value) error {
+	name := prior.GetAttr("tags").Index(cty.StringVal("name")).AsString()
+	if failDestroy[name] {
+		return fmt.Errorf("cannot destroy %s", name)
+	}
+	destroyed = append(destroyed, name)
+	destroyEvents = append(destroyEvents, "destroy "+name)
+	return nil
+}
+
+type destroySuite struct {
+	suite.Suite
+	*testBase
+}
+
+func TestDestroySuite(t *testing.T) {
+	suite.Run(t, new(destroySuite))
+}
+
+func (s *destroySuite) SetupTest() {
+	s.testBase = newTestBase()
+	resetDestroyRecords()
+}
+
+func resetDestroyRecords() {
+	destroyed = nil
+	destroyEvents = nil
+	failDestroy = map[string]bool{}
+}
+
+func (s *destroySuite) TearDownTest() {
+	s.teardown()
+}
+
+func (s *destroySuite) apply(config string) error {
+	require.NoError(s.T(), afero.WriteFile(s.fs, "/main.hcl", []byte(config), 0644))
+	hclBlocks, err := loadHclBlocks(false, "/")
+	require.NoError(s.T(), err)
+	c := &DummyConfig{
+		BaseConfig: NewBasicConfigFromArgs(NewBaseConfigArgs{
+			Basedir:         "/",
+			DslFullName:     "faketerraform",
+			DslAbbreviation: "ft",
+			StatePath:       "/state.json",
+		}),
+	}
+	require.NoError(s.T(), InitConfig(c, hclBlocks))
+	require.NoError(s.T(), c.RunPlan())
+	return c.RunApply()
+}
+
+func (s *destroySuite) stateAddresses() []string {
+	c := NewBasicConfigFromArgs(NewBaseConfigArgs{StatePath: "/state.json"})
+	state, err := c.readState()
+	require.NoError(s.T(), err)
+	return sortedKeys(state.Blocks)
+}
+
+const destroyChainConfig = `locals {
+  keys = ["a", "b"]
+}
+
+resource "destroyable" "base" {
+  tags = {
+    name = "base"
+  }
+}
+
+resource "destroyable" "middle" {
+  tags = {
+    name = "middle"
+    base = resource.destroyable.base.tags.name
+  }
+}
+
+resource "destroyable" "top" {
+  for_each = toset(local.keys)
+  tags = {
+    name   = "top-${each.value}"
+    middle = resource.destroyable.middle.tags.name
+  }
+}
+
+resource "dummy" "plain" {
+}
+`
+
+func (s *destroySuite) TestDestroy_ReverseDependencyOrder() {
+	require.NoError(s.T(), s.apply(destroyChainConfig))
+	s.Empty(destroyed)
+	s.Len(s.stateAddresses(), 5)
+
+	require.NoError(s.T(), s.apply(`resource "destroyable" "other" {
+  tags = {
+    name = "other"
+  }
+}
+`))
+	s.Equal([]string{"top-a", "top-b", "middle", "base"}, destroyed)
+	s.Equal([]string{"resource.destroyable.other"}, s.stateAddresses())
+}
+
+func (s *destroySuite) TestDestroy_InstanceRemovedFromForEach() {
+	require.NoError(s.T(), s.apply(destroyChainConfig))
+	require.NoError(s.T(), s.apply(strings.Replace(destroyChainConfig, `keys = ["a", "b"]`, `keys = ["a"]`, 1)))
+	s.Equal([]string{"top-b"}, destroyed)
+	s.NotContains(s.stateAddresses(), "resource.destroyable.top[b]")
+	s.Contains(s.stateAddresses(), "resource.destroyable.top[a]")
+}
+
+func (s *destroySuite) TestDestroy_FailureKeepsDependencies() {
+	require.NoError(s.T(), s.apply(destroyChainConfig))
+	failDestroy["middle"] = true
+	err := s.apply(`resource "dummy" "plain" {
+}
+`)
+	s.ErrorContains(err, "cannot destroy middle")
+	s.Equal([]string{"top-a", "top-b"}, destroyed)
+	s.Equal([]string{"resource.destroyable.base", "resource.destroyable.middle", "resource.dummy.plain"}, s.stateAddresses())
+}
+
+func (s *destroySuite) TestDestroy_RemovedModule() {
+	require.NoError(s.T(), afero.WriteFile(s.fs, "/child/main.hcl", []byte(`resource "destroyable" "base" {
+  tags = {
+    name = "base"
+  }
+}
+
+resource "destroyable" "middle" {
+  tags = {
+    name = "middle"
+    base = resource.destroyable.base.tags.name
+  }
+}
+`), 0644))
+	moduleConfig := `module "child" {
+  source = "./child"
+}
+`
+	require.NoError(s.T(), s.apply(moduleConfig))
+	s.Equal([]string{"module.child", "module.child.resource.destroyable.base", "module.child.resource.destroyable.middle"}, s.stateAddresses())
+	c := NewBasicConfigFromArgs(NewBaseConfigArgs{StatePath: "/state.json"})
+	state, err := c.readState()
+	require.NoError(s.T(), err)
+	s.Equal([]string{"module.child.resource.destroyable.base"}, state.Blocks["module.child.resource.destroyable.middle"].DependsOn)
+
+	require.NoError(s.T(), s.apply(moduleConfig))
+	s.Empty(destroyed)
+
+	require.NoError(s.T(), s.apply(`resource "destroyable" "other" {
+  tags = {
+    name = "other"
+  }
+}
+`))
+	s.Equal([]string{"middle", "base"}, destroyed)
+	s.Equal([]string{"resource.destroyable.other"}, s.stateAddresses())
+}
@@ -0,0 +1,123 @@
+package golden
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/go-multierror"
+)
+
+type EventType string
+
+const (
+	EventBlockQueued        EventType = "block_queued"
+	EventBlockExpanded      EventType = "block_expanded"
+	EventDecodeStarted      EventType = "decode_started"
+	EventDecodeFinished     EventType = "decode_finished"
+	EventPreconditionFailed EventType = "precondition_failed"
+	EventExecuteStarted     EventType = "execute_started"
+	EventExecuteFinished    EventType = "execute_finished"
+	EventBlockSkipped       EventType = "block_skipped"
+)
+
+// Event is a Hook callback captured by EventStream, only fields relevant to the Type are set.
+type Event struct {
+	Type      EventType
+	Time      time.Time
+	Address   string
+	Phase     string
+	Instances []string
+	Duration  time.Duration
+	Err       error
+}
+
+var _ Hook = &EventStream{}
+
+// EventStream is a Hook that publishes callbacks as Event to a channel so UIs can consume them in another goroutine.
+// Hooks are called synchronously, so the run blocks when the buffer is full until events are received or the stream is closed.
+type EventStream struct {
+	mu      sync.Mutex
+	events  chan Event
+	done    chan struct{}
+	closed  bool
+	senders sync.WaitGroup
+}
+
+func NewEventStream(buffer int) *EventStream {
+	return &EventStream{
+		events: make(chan Event, buffer),
+		done:   make(chan struct{}),
+	}
+}
+
+func (s *EventStream) Events() <-chan Event {
+	return s.events
+}
+
+// Close closes the events channel, events published after Close, or blocked on a full buffer when Close is called, are dropped.
+func (s *EventStream) Close() {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return
+	}
+	s.closed = true
+	close(s.done)
+	s.mu.Unlock()
+	// pending sends return once done is closed, so the events channel is never closed while being sent to.
+	s.senders.Wait()
+	close(s.events)
+}
+
+func (s *EventStream) publish(e Event) {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return
+	}
+	s.senders.Add(1)
+	s.mu.Unlock()
+	defer s.senders.Done()
+	e.Time = time.Now()
+	select {
+	case s.events <- e:
+	case <-s.done:
+	}
+}
+
+func (s *EventStream) BlockQueued(address string) {
+	s.publish(Event{Type: EventBlockQueued, Address: address})
+}
+
+func (s *EventStream) BlockExpanded(address string, instances []string) {
+	s.publish(Event{Type: EventBlockExpanded, Address: address, Instances: instances})
+}
+
+func (s *EventStream) DecodeStarted(b Block) {
+	s.publish(Event{Type: EventDecodeStarted, Address: FullAddress(b)})
+}
+
+func (s *EventStream) DecodeFinished(b Block, err error) {
+	s.publish(Event{Type: EventDecodeFinished, Address: FullAddress(b), Err: err})
+}
+
+func (s *EventStream) PreconditionFailed(b Block, failed []PreCondition) {
+	var err error
+	for _, c := range failed {
+		err = multierror.Append(err, fmt.Errorf("precondition check error: %s, %s", c.ErrorMessage, c.Body.Range().String()))
+	}
+	s.publish(Event{Type: EventPreconditionFailed, Address: FullAddress(b), Err: err})
+}
+
+func (s *EventStream) ExecuteStarted(b Block, phase string) {
+	s.publish(Event{Type: EventExecuteStarted, Address: FullAddress(b), Phase: phase})
+}
+
+func (s *EventStream) ExecuteFinished(b Block, phase string, duration time.Duration, err error) {
+	s.publish(Event{Type: EventExecuteFinished, Address: FullAddress(b), Phase: phase, Duration: duration, Err: err})
+}
+
+func (s *EventStream) BlockSkipped(address string) {
+	s.publish(Event{Type: EventBlockSkipped, Address: address})
+}
@@ -0,0 +1,161 @@
+package golden
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type eventStreamSuite struct {
+	suite.Suite
+	*testBase
+}
+
+func TestEventStreamSuite(t *testing.T) {
+	suite.Run(t, new(eventStreamSuite))
+}
+
+func (s *eventStreamSuite) SetupTest() {
+	s.testBase = newTestBase()
+}
+
+func (s *eventStreamSuite) TearDownTest() {
+	s.teardown()
+}
+
+func (s *eventStreamSuite) TestEventStream_ConsumedByAnotherGoroutine() {
+	s.dummyFsWithFiles(map[string]string{
+		"/main.hcl": `data "dummy" "foo" {
+}
+
+data "dummy" "bar" {
+  data = data.dummy.foo.data
+  precondition {
+    condition     = false
+    error_message = "bar is not ready"
+  }
+}
+`,
+	})
+	hclBlocks, err := loadHclBlocks(false, "/")
+	require.NoError(s.T(), err)
+	stream := NewEventStream(0)
+	c := &DummyConfig{
+		BaseConfig: NewBasicConfigFromArgs(NewBaseConfigArgs{
+			Basedir:         "/",
+			DslFullName:     "faketerraform",
+			DslAbbreviation: "ft",
+			Ctx:             context.Background(),
+			Hooks:           []Hook{stream},
+		}),
+	}
+	var events []Event
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for e := range stream.Events() {
+			events = append(events, e)
+		}
+	}()
+	require.NoError(s.T(), InitConfig(c, hclBlocks))
+	s.NotNil(c.RunPlan())
+	stream.Close()
+	<-done
+
+	var types []EventType
+	for _, e := range events {
+		s.False(e.Time.IsZero())
+		if e.Address == "data.dummy.bar" {
+			types = append(types, e.Type)
+		}
+	}
+	s.Equal([]EventType{
+		EventBlockQueued,
+		EventDecodeStarted,
+		EventDecodeFinished,
+		EventPreconditionFailed,
+	}, types)
+	last := events[len(events)-1]
+	s.Equal(EventPreconditionFailed, last.Type)
+	s.ErrorContains(last.Err, "bar is not ready")
+}
+
+func (s *eventStreamSuite) TestEventStream_PublishAfterCloseIsDropped() {
+	stream := NewEventStream(1)
+	stream.Close()
+	stream.Close()
+	stream.BlockSkipped("data.dummy.foo")
+	_, ok := <-stream.Events()
+	s.False(ok)
+}
+
+func (s *eventStreamSuite) TestEventStream_CloseUnblocksPendingPublish() {
+	stream := NewEventStream(1)
+	stream.BlockQueued("data.dummy.foo")
+	published := make(chan struct{})
+	go func() {
+		defer close(published)
+		// the buffer is full and nobody is receiving, so this publish blocks until Close.
+		stream.BlockQueued("data.dummy.bar")
+	}()
+	closed := make(chan struct{})
+	go func() {
+		defer close(closed)
+		time.Sleep(10 * time.Millisecond)
+		stream.Close()
+	}()
+	for _, c := range []chan struct{}{published, closed} {
+		select {
+		case <-c:
+		case <-time.After(5 * time.Second):
+			s.FailNow("event stream deadlocked")
+		}
+	}
+	e, ok := <-stream.Events()
+	s.True(ok)
+	s.Equal("data.dummy.foo", e.Address)
+	_, ok = <-stream.Events()
+	s.False(ok)
+}
+
+func (s *eventStreamSuite) TestEventStream_ModuleBlocksHaveFullAddresses() {
+	s.dummyFsWithFiles(map[string]string{
+		"/main.hcl": `data "dummy" "bar" {
+}
+
+module "child" {
+  source = "./child"
+}
+`,
+		"/child/main.hcl": `data "dummy" "bar" {
+}
+`,
+	})
+	hclBlocks, err := loadHclBlocks(false, "/")
+	require.NoError(s.T(), err)
+	stream := NewEventStream(100)
+	c := &DummyConfig{
+		BaseConfig: NewBasicConfigFromArgs(NewBaseConfigArgs{
+			Basedir:         "/",
+			DslFullName:     "faketerraform",
+			DslAbbreviation: "ft",
+			Ctx:             context.Background(),
+			Hooks:           []Hook{stream},
+		}),
+	}
+	require.NoError(s.T(), InitConfig(c, hclBlocks))
+	require.NoError(s.T(), c.RunPlan())
+	stream.Close()
+
+	finished := make(map[string]int)
+	for e := range stream.Events() {
+		if e.Type == EventExecuteFinished {
+			finished[e.Address]++
+		}
+	}
+	s.Equal(1, finished["data.dummy.bar"])
+	s.Equal(1, finished["module.child.data.dummy.bar"])
+}
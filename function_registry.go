@@ -0,0 +1,196 @@
+package golden
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/go-multierror"
+	"github.com/hashicorp/hcl/v2/ext/typeexpr"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/lonegunmanb/hclfuncs"
+	"github.com/zclconf/go-cty/cty"
+	"github.com/zclconf/go-cty/cty/function"
+)
+
+type FunctionSource string
+
+const (
+	FunctionSourceBuiltin         FunctionSource = "builtin"
+	FunctionSourceBuiltinOverride FunctionSource = "builtin_override"
+	// FunctionSourceDslOverride is a function without namespace supplied by BaseConfig.OverrideFunctions.
+	FunctionSourceDslOverride FunctionSource = "dsl_override"
+	FunctionSourceRegistered  FunctionSource = "registered"
+	FunctionSourceConfig      FunctionSource = "config"
+	// FunctionSourceSandbox is a built-in function replaced by the sandbox, like `file`.
+	FunctionSourceSandbox FunctionSource = "sandbox"
+)
+
+type FunctionParam struct {
+	Name string
+	Type string
+}
+
+// FunctionSignature describes a function available in eval context.
+type FunctionSignature struct {
+	Name        string
+	Params      []FunctionParam
+	VarParam    *FunctionParam
+	ReturnType  string
+	Description string
+	Source      FunctionSource
+}
+
+func (s FunctionSignature) String() string {
+	var params []string
+	for _, p := range s.Params {
+		params = append(params, fmt.Sprintf("%s %s", p.Name, p.Type))
+	}
+	if s.VarParam != nil {
+		params = append(params, fmt.Sprintf("%s ...%s", s.VarParam.Name, s.VarParam.Type))
+	}
+	return fmt.Sprintf("%s(%s) %s", s.Name, strings.Join(params, ", "), s.ReturnType)
+}
+
+// RegisterFunction registers fn as `<namespace>::<name>`, like `provider::azure::parse_id`.
+// Namespace could contain multiple segments separated by `::`, registering a name twice is an error.
+func (c *BaseConfig) RegisterFunction(namespace, name string, fn function.Function) error {
+	if namespace == "" {
+		return fmt.Errorf("namespace is required for function %s, use OverrideBuiltinFunction to replace built-in functions", name)
+	}
+	for _, segment := range append(strings.Split(namespace, "::"), name) {
+		if !hclsyntax.ValidIdentifier(segment) {
+			return fmt.Errorf("invalid function name %s::%s, `%s` is not a valid identifier", namespace, name, segment)
+		}
+	}
+	fullName := namespace + "::" + name
+	if _, ok := c.registeredFunctions[fullName]; ok {
+		return fmt.Errorf("function %s has been registered already", fullName)
+	}
+	if c.registeredFunctions == nil {
+		c.registeredFunctions = make(map[string]function.Function)
+	}
+	c.registeredFunctions[fullName] = fn
+	return nil
+}
+
+// RegisterFunctions registers all functions in fns under namespace.
+func (c *BaseConfig) RegisterFunctions(namespace string, fns map[string]function.Function) error {
+	for _, name := range sortedKeys(fns) {
+		if err := c.RegisterFunction(namespace, name, fns[name]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// OverrideBuiltinFunction replaces a built-in function explicitly, it's an error if name is not a built-in function.
+func (c *BaseConfig) OverrideBuiltinFunction(name string, fn function.Function) error {
+	if _, ok := hclfuncs.Functions(c.basedir)[name]; !ok {
+		return fmt.Errorf("%s is not a built-in function", name)
+	}
+	if c.builtinOverrides == nil {
+		c.builtinOverrides = make(map[string]function.Function)
+	}
+	c.builtinOverrides[name] = fn
+	return nil
+}
+
+// checkOverrideFunctions rejects OverrideFunctions that would shadow built-in functions implicitly.
+func (c *BaseConfig) checkOverrideFunctions() error {
+	builtins := hclfuncs.Functions(c.basedir)
+	var err error
+	for _, n := range sortedKeys(c.OverrideFunctions) {
+		if _, ok := builtins[n]; ok {
+			err = multierror.Append(err, fmt.Errorf("OverrideFunctions cannot replace built-in function %s, use OverrideBuiltinFunction instead", n))
+		}
+	}
+	return err
+}
+
+// overrideFunctions returns OverrideFunctions except built-in names, so they never shadow built-in functions.
+func (c *BaseConfig) overrideFunctions() map[string]function.Function {
+	builtins := hclfuncs.Functions(c.basedir)
+	r := make(map[string]function.Function)
+	for n, fn := range c.OverrideFunctions {
+		if _, ok := builtins[n]; !ok {
+			r[n] = fn
+		}
+	}
+	return r
+}
+
+// ListFunctions returns signatures of all functions available in eval context, ordered by name.
+func (c *BaseConfig) ListFunctions() []FunctionSignature {
+	sources := make(map[string]FunctionSource)
+	for n := range hclfuncs.Functions(c.basedir) {
+		sources[n] = FunctionSourceBuiltin
+	}
+	for n := range c.overrideFunctions() {
+		sources[n] = FunctionSourceDslOverride
+	}
+	for n := range c.builtinOverrides {
+		sources[n] = FunctionSourceBuiltinOverride
+	}
+	for n := range c.registeredFunctions {
+		sources[n] = FunctionSourceRegistered
+	}
+	for n := range c.userFunctions() {
+		sources[n] = FunctionSourceConfig
+	}
+	for n := range c.sandboxFunctions() {
+		sources[n] = FunctionSourceSandbox
+	}
+	functions := c.EmptyEvalContext().Functions
+	var r []FunctionSignature
+	for _, n := range sortedKeys(functions) {
+		r = append(r, functionSignature(n, functions[n], sources[n]))
+	}
+	return r
+}
+
+func functionSignature(name string, fn function.Function, source FunctionSource) FunctionSignature {
+	s := FunctionSignature{
+		Name:        name,
+		Description: fn.Description(),
+		Source:      source,
+		ReturnType:  typeString(cty.DynamicPseudoType),
+	}
+	var argTypes []cty.Type
+	for _, p := range fn.Params() {
+		s.Params = append(s.Params, FunctionParam{
+			Name: p.Name,
+			Type: typeString(p.Type),
+		})
+		argTypes = append(argTypes, p.Type)
+	}
+	if p := fn.VarParam(); p != nil {
+		s.VarParam = &FunctionParam{
+			Name: p.Name,
+			Type: typeString(p.Type),
+		}
+	}
+	// return type of some functions depends on argument values, they're reported as `any`.
+	if t, err := returnType(fn, argTypes); err == nil {
+		s.ReturnType = typeString(t)
+	}
+	return s
+}
+
+func returnType(fn function.Function, argTypes []cty.Type) (t cty.Type, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("%+v", r)
+		}
+	}()
+	return fn.ReturnType(argTypes)
+}
+
+// typeString falls back to friendly name for types that cannot be written as type constraint, like capsule types.
+func typeString(t cty.Type) (s string) {
+	defer func() {
+		if r := recover(); r != nil {
+			s = t.FriendlyName()
+		}
+	}()
+	return typeexpr.TypeString(t)
+}
@@ -0,0 +1,111 @@
+package golden
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"github.com/zclconf/go-cty/cty"
+	"github.com/zclconf/go-cty/cty/function"
+	"github.com/zclconf/go-cty/cty/function/stdlib"
+)
+
+type functionRegistrySuite struct {
+	suite.Suite
+	*testBase
+}
+
+func TestFunctionRegistrySuite(t *testing.T) {
+	suite.Run(t, new(functionRegistrySuite))
+}
+
+func (s *functionRegistrySuite) SetupTest() {
+	s.testBase = newTestBase()
+}
+
+func (s *functionRegistrySuite) TearDownTest() {
+	s.teardown()
+}
+
+func (s *functionRegistrySuite) TestRegisterFunction_NamespacedCall() {
+	s.dummyFsWithFiles(map[string]string{
+		"/main.hcl": `locals {
+  upper  = provider::azure::upper("a")
+  native = upper("b")
+}
+`,
+	})
+	c := NewBasicConfig("/", "faketerraform", "ft", nil, nil, nil)
+	require.NoError(s.T(), c.RegisterFunction("provider::azure", "upper", stdlib.LowerFunc))
+	blocks, err := LoadHclBlocks(false, "/")
+	require.NoError(s.T(), err)
+	require.NoError(s.T(), InitConfig(c, blocks))
+	locals := c.EvalContext().Variables["local"]
+	s.Equal(cty.StringVal("a"), locals.GetAttr("upper"))
+	s.Equal(cty.StringVal("B"), locals.GetAttr("native"))
+}
+
+func (s *functionRegistrySuite) TestRegisterFunction_InvalidRegistration() {
+	c := NewBasicConfig("/", "faketerraform", "ft", nil, nil, nil)
+	s.ErrorContains(c.RegisterFunction("", "upper", stdlib.LowerFunc), "namespace is required")
+	s.ErrorContains(c.RegisterFunction("provider::1azure", "f", stdlib.LowerFunc), "`1azure` is not a valid identifier")
+	s.NoError(c.RegisterFunctions("ns", map[string]function.Function{
+		"lower": stdlib.LowerFunc,
+	}))
+	s.ErrorContains(c.RegisterFunction("ns", "lower", stdlib.LowerFunc), "function ns::lower has been registered already")
+	s.ErrorContains(c.OverrideBuiltinFunction("not_builtin", stdlib.LowerFunc), "not_builtin is not a built-in function")
+}
+
+func (s *functionRegistrySuite) TestOverrideBuiltinFunction() {
+	c := NewBasicConfig("/", "faketerraform", "ft", nil, nil, nil)
+	require.NoError(s.T(), c.OverrideBuiltinFunction("upper", stdlib.LowerFunc))
+	v, err := c.EmptyEvalContext().Functions["upper"].Call([]cty.Value{cty.StringVal("A")})
+	require.NoError(s.T(), err)
+	s.Equal(cty.StringVal("a"), v)
+}
+
+func (s *functionRegistrySuite) TestOverrideFunctions_CannotShadowBuiltinFunction() {
+	s.dummyFsWithFiles(map[string]string{
+		"/main.hcl": `locals {
+  a = upper("a")
+}
+`,
+	})
+	c := NewBasicConfig("/", "faketerraform", "ft", nil, nil, nil)
+	c.OverrideFunctions = map[string]function.Function{
+		"upper":    stdlib.LowerFunc,
+		"my_lower": stdlib.LowerFunc,
+	}
+	s.Equal(cty.StringVal("A"), s.call(c, "upper", "a"))
+	s.Equal(cty.StringVal("a"), s.call(c, "my_lower", "A"))
+	blocks, err := LoadHclBlocks(false, "/")
+	require.NoError(s.T(), err)
+	err = InitConfig(c, blocks)
+	s.ErrorContains(err, "OverrideFunctions cannot replace built-in function upper, use OverrideBuiltinFunction instead")
+	s.NotContains(err.Error(), "my_lower")
+}
+
+func (s *functionRegistrySuite) call(c *BaseConfig, name, arg string) cty.Value {
+	v, err := c.EmptyEvalContext().Functions[name].Call([]cty.Value{cty.StringVal(arg)})
+	require.NoError(s.T(), err)
+	return v
+}
+
+func (s *functionRegistrySuite) TestListFunctions() {
+	c := NewBasicConfig("/", "faketerraform", "ft", nil, nil, nil)
+	require.NoError(s.T(), c.RegisterFunction("provider::azure", "lower", stdlib.LowerFunc))
+	require.NoError(s.T(), c.OverrideBuiltinFunction("upper", stdlib.LowerFunc))
+	c.OverrideFunctions = map[string]function.Function{
+		"my_lower": stdlib.LowerFunc,
+	}
+	signatures := make(map[string]FunctionSignature)
+	for _, sig := range c.ListFunctions() {
+		signatures[sig.Name] = sig
+	}
+	s.Equal(FunctionSourceRegistered, signatures["provider::azure::lower"].Source)
+	s.Equal("provider::azure::lower(str string) string", signatures["provider::azure::lower"].String())
+	s.Equal(FunctionSourceBuiltinOverride, signatures["upper"].Source)
+	s.Equal(FunctionSourceDslOverride, signatures["my_lower"].Source)
+	s.Equal(FunctionSourceBuiltin, signatures["concat"].Source)
+	s.NotNil(signatures["concat"].VarParam)
+}
@@ -2,6 +2,8 @@ package golden
 
 import (
 	"fmt"
+	"sort"
+
 	"github.com/hashicorp/hcl/v2"
 	"github.com/hashicorp/hcl/v2/hclsyntax"
 	"github.com/hashicorp/hcl/v2/hclwrite"
@@ -74,6 +76,12 @@ func (hb *HclBlock) ExpandDynamicBlocks(evalContext *hcl.EvalContext) (*HclBlock
 	}
 	var newNestedBlocks []*hclsyntax.Block
 	for _, block := range hb.blocks {
+		// meta nested blocks like `precondition` are evaluated by their own checks, with their own eval context.
+		if block.Type != "dynamic" && MetaNestedBlockNames.Contains(block.Type) {
+			newHb.blocks = append(newHb.blocks, block)
+			newNestedBlocks = append(newNestedBlocks, block.Block)
+			continue
+		}
 		if block.Type != "dynamic" {
 			expandedBlock, err := block.ExpandDynamicBlocks(evalContext)
 			if err != nil {
@@ -140,7 +148,9 @@ func readRawHclSyntaxBlock(b *hclsyntax.Block) []*hclsyntax.Block {
 	case "locals":
 		{
 			var newBlocks []*hclsyntax.Block
-			for _, attr := range b.Body.Attributes {
+			// sorted by name so local blocks could be paired with blocks from readRawHclWriteBlock.
+			for _, n := range sortedKeys(b.Body.Attributes) {
+				attr := b.Body.Attributes[n]
 				newBlocks = append(newBlocks, &hclsyntax.Block{
 					Type:   "local",
 					Labels: []string{"", attr.Name},
@@ -157,6 +167,9 @@ func readRawHclSyntaxBlock(b *hclsyntax.Block) []*hclsyntax.Block {
 						SrcRange: attr.NameRange,
 						EndRange: attr.SrcRange,
 					},
+					// the range of a local block is the range of its attribute in `locals`.
+					TypeRange:       attr.NameRange,
+					CloseBraceRange: attr.SrcRange,
 				})
 			}
 			return newBlocks
@@ -197,7 +210,9 @@ func readRawHclWriteBlock(b *hclwrite.Block) []*hclwrite.Block {
 		return []*hclwrite.Block{b}
 	}
 	var newBlocks []*hclwrite.Block
-	for n, attr := range b.Body().Attributes() {
+	attributes := b.Body().Attributes()
+	for _, n := range sortedKeys(attributes) {
+		attr := attributes[n]
 		nb := hclwrite.NewBlock("local", []string{"", n})
 		nb.Body().SetAttributeRaw("value", attr.Expr().BuildTokens(hclwrite.Tokens{}))
 		newBlocks = append(newBlocks, nb)
@@ -205,6 +220,15 @@ func readRawHclWriteBlock(b *hclwrite.Block) []*hclwrite.Block {
 	return newBlocks
 }
 
+func sortedKeys[T any](m map[string]T) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
 func clone[T any](v *T) *T {
 	c := *v
 	return &c
@@ -1,6 +1,9 @@
 package golden
 
 import (
+	"fmt"
+	"strings"
+
 	"github.com/prashantv/gostub"
 	"github.com/spf13/afero"
 	"github.com/stretchr/testify/require"
@@ -248,3 +251,25 @@ self_ref that {
 		})
 	}
 }
+
+func TestAsHclBlocks_LocalsPairedByName(t *testing.T) {
+	sb := strings.Builder{}
+	sb.WriteString("locals {\n")
+	for i := 0; i < 20; i++ {
+		fmt.Fprintf(&sb, "  local%d = \"value%d\"\n", i, i)
+	}
+	sb.WriteString("}\n")
+	syntaxFile, diag := hclsyntax.ParseConfig([]byte(sb.String()), "test.hcl", hcl.InitialPos)
+	require.False(t, diag.HasErrors())
+	writeFile, diag := hclwrite.ParseConfig([]byte(sb.String()), "test.hcl", hcl.InitialPos)
+	require.False(t, diag.HasErrors())
+
+	blocks := AsHclBlocks(syntaxFile.Body.(*hclsyntax.Body).Blocks, writeFile.Body().Blocks())
+	require.Len(t, blocks, 20)
+	for _, b := range blocks {
+		name := b.Labels[1]
+		assert.Equal(t, name, b.wb.Labels()[1])
+		value := string(b.wb.Body().GetAttribute("value").Expr().BuildTokens(nil).Bytes())
+		assert.Equal(t, fmt.Sprintf(`"value%s"`, strings.TrimPrefix(name, "local")), strings.TrimSpace(value))
+	}
+}
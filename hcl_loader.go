@@ -0,0 +1,90 @@
+package golden
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/hashicorp/go-multierror"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/spf13/afero"
+)
+
+// LoadHclBlocks reads all `*.hcl` files in dir and returns registered blocks, blocks in override files are merged into blocks in other files.
+func LoadHclBlocks(ignoreUnsupportedBlock bool, dir string) ([]*HclBlock, error) {
+	return loadHclBlocksFromFs(configFs, ignoreUnsupportedBlock, dir)
+}
+
+func loadHclBlocksFromFs(fs afero.Fs, ignoreUnsupportedBlock bool, dir string) ([]*HclBlock, error) {
+	return loadLayeredHclBlocksFromFs(fs, ignoreUnsupportedBlock, []string{dir})
+}
+
+// loadLayer returns blocks in dir with override files merged, and `removed` blocks in dir.
+func loadLayer(fs afero.Fs, ignoreUnsupportedBlock bool, dir string) ([]*HclBlock, []*HclBlock, error) {
+	matches, err := afero.Glob(fs, filepath.Join(dir, "*.hcl"))
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(matches) == 0 {
+		return nil, nil, fmt.Errorf("no `.hcl` file found at %s", dir)
+	}
+
+	var blocks, overrideBlocks []*HclBlock
+
+	for _, filename := range matches {
+		content, fsErr := afero.ReadFile(fs, filename)
+		if fsErr != nil {
+			err = multierror.Append(err, fsErr)
+			continue
+		}
+		readFile, diag := hclsyntax.ParseConfig(content, filename, hcl.InitialPos)
+		if diag.HasErrors() {
+			err = multierror.Append(err, diag.Errs()...)
+			continue
+		}
+		writeFile, diag := hclwrite.ParseConfig(content, filename, hcl.InitialPos)
+		if diag.HasErrors() {
+			err = multierror.Append(err, diag.Errs()...)
+			continue
+		}
+		fileBlocks := AsHclBlocks(readFile.Body.(*hclsyntax.Body).Blocks, writeFile.Body().Blocks())
+		if isOverrideFile(filename) {
+			overrideBlocks = append(overrideBlocks, fileBlocks...)
+			continue
+		}
+		blocks = append(blocks, fileBlocks...)
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var r, overrides, removed []*HclBlock
+	for _, b := range blocks {
+		if b.Type == removedBlockType {
+			removed = append(removed, b)
+			continue
+		}
+		if IsBlockTypeWanted(b.Type) {
+			r = append(r, b)
+			continue
+		}
+		if !ignoreUnsupportedBlock {
+			err = multierror.Append(err, fmt.Errorf("invalid block type: %s %s", b.Type, b.Range().String()))
+		}
+	}
+	for _, b := range overrideBlocks {
+		if IsBlockTypeWanted(b.Type) {
+			overrides = append(overrides, b)
+			continue
+		}
+		if !ignoreUnsupportedBlock {
+			err = multierror.Append(err, fmt.Errorf("invalid block type: %s %s", b.Type, b.Range().String()))
+		}
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	r, err = applyOverrides(r, overrides)
+	return r, removed, err
+}
@@ -0,0 +1,54 @@
+package golden
+
+import (
+	"time"
+)
+
+// Hook observes progress of RunPrePlan, RunPlan and RunApply, callbacks are called synchronously in the order of execution.
+// Addresses passed to callbacks are full addresses like `module.child.resource.dummy.bar` for blocks in modules, use FullAddress(b) for callbacks receiving blocks.
+// Embed NopHook to implement only the callbacks you need.
+type Hook interface {
+	// BlockQueued is called when all upstreams of the block are ready and it's about to be expanded or executed.
+	BlockQueued(address string)
+	// BlockExpanded is called when a block with `for_each` has been expanded to instances.
+	BlockExpanded(address string, instances []string)
+	DecodeStarted(b Block)
+	DecodeFinished(b Block, err error)
+	PreconditionFailed(b Block, failed []PreCondition)
+	// ExecuteStarted and ExecuteFinished wrap ExecuteDuringPlan for the `plan` phase and Apply for the `apply` phase, including retries.
+	ExecuteStarted(b Block, phase string)
+	ExecuteFinished(b Block, phase string, duration time.Duration, err error)
+	// BlockSkipped is called at the end of a run for blocks that have not been executed because an upstream block failed.
+	BlockSkipped(address string)
+}
+
+var _ Hook = NopHook{}
+
+type NopHook struct{}
+
+func (NopHook) BlockQueued(string)                                  {}
+func (NopHook) BlockExpanded(string, []string)                      {}
+func (NopHook) DecodeStarted(Block)                                 {}
+func (NopHook) DecodeFinished(Block, error)                         {}
+func (NopHook) PreconditionFailed(Block, []PreCondition)            {}
+func (NopHook) ExecuteStarted(Block, string)                        {}
+func (NopHook) ExecuteFinished(Block, string, time.Duration, error) {}
+func (NopHook) BlockSkipped(string)                                 {}
+
+// AddHook registers h, hooks are inherited by child configs of modules.
+func (c *BaseConfig) AddHook(h Hook) {
+	c.hooks = append(c.hooks, h)
+}
+
+func configHooks(c Config) []Hook {
+	if bc, ok := c.(interface{ baseConfig() *BaseConfig }); ok {
+		return bc.baseConfig().hooks
+	}
+	return nil
+}
+
+func notifyHooks(c Config, f func(h Hook)) {
+	for _, h := range configHooks(c) {
+		f(h)
+	}
+}
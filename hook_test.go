@@ -0,0 +1,149 @@
+package golden
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type recordingHook struct {
+	NopHook
+	calls []string
+}
+
+func (h *recordingHook) BlockQueued(address string) {
+	h.calls = append(h.calls, "queued "+address)
+}
+
+func (h *recordingHook) BlockExpanded(address string, instances []string) {
+	h.calls = append(h.calls, fmt.Sprintf("expanded %s %v", address, instances))
+}
+
+func (h *recordingHook) DecodeStarted(b Block) {
+	h.calls = append(h.calls, "decode started "+b.Address())
+}
+
+func (h *recordingHook) DecodeFinished(b Block, err error) {
+	h.calls = append(h.calls, fmt.Sprintf("decode finished %s %t", b.Address(), err == nil))
+}
+
+func (h *recordingHook) PreconditionFailed(b Block, failed []PreCondition) {
+	h.calls = append(h.calls, fmt.Sprintf("precondition failed %s %s", b.Address(), failed[0].ErrorMessage))
+}
+
+func (h *recordingHook) ExecuteStarted(b Block, phase string) {
+	h.calls = append(h.calls, fmt.Sprintf("%s started %s", phase, b.Address()))
+}
+
+func (h *recordingHook) ExecuteFinished(b Block, phase string, duration time.Duration, err error) {
+	h.calls = append(h.calls, fmt.Sprintf("%s finished %s %t", phase, b.Address(), err == nil))
+}
+
+func (h *recordingHook) BlockSkipped(address string) {
+	h.calls = append(h.calls, "skipped "+address)
+}
+
+type hookSuite struct {
+	suite.Suite
+	*testBase
+}
+
+func TestHookSuite(t *testing.T) {
+	suite.Run(t, new(hookSuite))
+}
+
+func (s *hookSuite) SetupTest() {
+	s.testBase = newTestBase()
+}
+
+func (s *hookSuite) TearDownTest() {
+	s.teardown()
+}
+
+func (s *hookSuite) config(hook Hook, config string) *DummyConfig {
+	s.dummyFsWithFiles(map[string]string{
+		"/main.hcl": config,
+	})
+	hclBlocks, err := loadHclBlocks(false, "/")
+	require.NoError(s.T(), err)
+	c := &DummyConfig{
+		BaseConfig: NewBasicConfigFromArgs(NewBaseConfigArgs{
+			Basedir:         "/",
+			DslFullName:     "faketerraform",
+			DslAbbreviation: "ft",
+			Ctx:             context.Background(),
+			Hooks:           []Hook{hook},
+		}),
+	}
+	require.NoError(s.T(), InitConfig(c, hclBlocks))
+	return c
+}
+
+func (s *hookSuite) TestHook_PlanAndApply() {
+	hook := &recordingHook{}
+	config := s.config(hook, `resource "dummy" "foo" {
+  for_each = toset(["a", "b"])
+  tags = {
+    key = each.value
+  }
+}
+`)
+	s.Equal([]string{
+		"queued resource.dummy.foo",
+		"expanded resource.dummy.foo [resource.dummy.foo[a] resource.dummy.foo[b]]",
+	}, hook.calls[:2])
+
+	hook.calls = nil
+	require.NoError(s.T(), config.RunPlan())
+	s.Len(hook.calls, 10)
+	for _, address := range []string{"resource.dummy.foo[a]", "resource.dummy.foo[b]"} {
+		var calls []string
+		for _, c := range hook.calls {
+			if strings.HasSuffix(c, address) || strings.Contains(c, address+" ") {
+				calls = append(calls, c)
+			}
+		}
+		s.Equal([]string{
+			"queued " + address,
+			"decode started " + address,
+			"decode finished " + address + " true",
+			"plan started " + address,
+			"plan finished " + address + " true",
+		}, calls)
+	}
+
+	hook.calls = nil
+	require.NoError(s.T(), config.RunApply())
+	s.Contains(hook.calls, "apply started resource.dummy.foo[a]")
+	s.Contains(hook.calls, "apply finished resource.dummy.foo[b] true")
+}
+
+func (s *hookSuite) TestHook_PreconditionFailedAndSkipped() {
+	s.dummyFsWithFiles(map[string]string{
+		"/main.hcl": `data "dummy" "foo" {
+  precondition {
+    condition     = false
+    error_message = "foo is not ready"
+  }
+}
+
+data "dummy" "bar" {
+  data = data.dummy.foo.data
+}
+`,
+	})
+	config, err := BuildDummyConfig("/", "/", nil, nil)
+	require.NoError(s.T(), err)
+	hook := &recordingHook{}
+	config.(*DummyConfig).AddHook(hook)
+	s.NotNil(config.RunPlan())
+	s.Contains(hook.calls, "precondition failed data.dummy.foo foo is not ready")
+	s.Contains(hook.calls, "skipped data.dummy.bar")
+	s.NotContains(hook.calls, "queued data.dummy.bar")
+	s.NotContains(hook.calls, "skipped data.dummy.foo")
+}
@@ -7,6 +7,8 @@ func init() {
 	RegisterBaseBlock(func() BlockType { return new(BaseResource) })
 	RegisterBlock(new(DummyData))
 	RegisterBlock(new(DummyResource))
+	RegisterBlock(new(DestroyableResource))
+	RegisterBlock(new(FlakyResource))
 	RegisterBlock(new(PureApplyBlock))
 	RegisterBlock(new(PureApplyBlock2))
 	RegisterBlock(new(DummyRootBlock))
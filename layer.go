@@ -0,0 +1,107 @@
+package golden
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/go-multierror"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/spf13/afero"
+)
+
+// removedBlockType is the marker block that removes a block defined by previous layers: `removed { from = data.foo.bar }`.
+const removedBlockType = "removed"
+
+// LoadLayeredHclBlocks loads dirs in order as layers, a later layer can add blocks, replace blocks with the same address, or remove blocks by `removed` block.
+// Ranges of final blocks point to files of the layer that defined them.
+func LoadLayeredHclBlocks(ignoreUnsupportedBlock bool, dirs []string) ([]*HclBlock, error) {
+	return loadLayeredHclBlocksFromFs(configFs, ignoreUnsupportedBlock, dirs)
+}
+
+func loadLayeredHclBlocksFromFs(fs afero.Fs, ignoreUnsupportedBlock bool, dirs []string) ([]*HclBlock, error) {
+	var r []*HclBlock
+	for _, dir := range dirs {
+		blocks, removed, err := loadLayer(fs, ignoreUnsupportedBlock, dir)
+		if err != nil {
+			return nil, err
+		}
+		if r, err = removeBlocks(r, removed); err != nil {
+			return nil, err
+		}
+		r = replaceBlocks(r, blocks)
+	}
+	return r, nil
+}
+
+func removeBlocks(blocks, removed []*HclBlock) ([]*HclBlock, error) {
+	if len(removed) == 0 {
+		return blocks, nil
+	}
+	var err error
+	targets := make(map[string]*HclBlock)
+	for _, rb := range removed {
+		address, diag := removedAddress(rb)
+		if diag.HasErrors() {
+			err = multierror.Append(err, diag)
+			continue
+		}
+		targets[address] = rb
+	}
+	var r []*HclBlock
+	for _, b := range blocks {
+		address := layerAddress(b)
+		if _, ok := targets[address]; ok {
+			delete(targets, address)
+			continue
+		}
+		r = append(r, b)
+	}
+	for _, rb := range removed {
+		address, _ := removedAddress(rb)
+		if _, ok := targets[address]; ok {
+			err = multierror.Append(err, fmt.Errorf("`removed` %s targets non-existent block %s", rb.Range().String(), address))
+		}
+	}
+	return r, err
+}
+
+func replaceBlocks(blocks, layer []*HclBlock) []*HclBlock {
+	indexes := make(map[string]int)
+	for i, b := range blocks {
+		indexes[layerAddress(b)] = i
+	}
+	for _, b := range layer {
+		if i, ok := indexes[layerAddress(b)]; ok {
+			blocks[i] = b
+			continue
+		}
+		blocks = append(blocks, b)
+	}
+	return blocks
+}
+
+func removedAddress(rb *HclBlock) (string, hcl.Diagnostics) {
+	attr, ok := rb.Body.Attributes["from"]
+	if !ok {
+		return "", hcl.Diagnostics{&hcl.Diagnostic{
+			Severity: hcl.DiagError,
+			Summary:  "Missing `from`",
+			Detail:   "`removed` block requires `from` attribute with the address of the removed block",
+			Subject:  rb.Range().Ptr(),
+		}}
+	}
+	traversal, diag := hcl.AbsTraversalForExpr(attr.Expr)
+	if diag.HasErrors() {
+		return "", diag
+	}
+	return traversalString(traversal), nil
+}
+
+// layerAddress is the address used by references, like `var.foo` for `variable "foo"`.
+func layerAddress(hb *HclBlock) string {
+	address := blockAddress(hb)
+	if s, ok := blockSamples[hb.Type].(BlockCustomizedRefType); ok {
+		return s.CustomizedRefType() + strings.TrimPrefix(address, hb.Type)
+	}
+	return address
+}
@@ -0,0 +1,136 @@
+package golden
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"github.com/zclconf/go-cty/cty"
+)
+
+type layerSuite struct {
+	suite.Suite
+	*testBase
+}
+
+func TestLayerSuite(t *testing.T) {
+	suite.Run(t, new(layerSuite))
+}
+
+func (s *layerSuite) SetupTest() {
+	s.testBase = newTestBase()
+}
+
+func (s *layerSuite) TearDownTest() {
+	s.teardown()
+}
+
+func (s *layerSuite) TestLoadLayeredHclBlocks_AddReplaceAndRemove() {
+	s.dummyFsWithFiles(map[string]string{
+		"/base/main.hcl": `data "dummy" foo {
+  data = {
+    key = "base"
+  }
+}
+
+data "dummy" bar {
+}
+
+locals {
+  a = "base_a"
+  b = "base_b"
+}
+
+variable "v" {
+  default = "base"
+}
+`,
+		"/dev/main.hcl": `data "dummy" foo {
+  data = {
+    key = "dev"
+  }
+}
+
+locals {
+  c = "dev_c"
+}
+
+removed {
+  from = data.dummy.bar
+}
+
+removed {
+  from = local.b
+}
+
+removed {
+  from = var.v
+}
+`,
+	})
+	blocks, err := LoadLayeredHclBlocks(false, []string{"/base", "/dev"})
+	require.NoError(s.T(), err)
+	config, err := NewDummyConfig("/dev", nil, blocks, nil)
+	require.NoError(s.T(), err)
+	_, err = RunDummyPlan(config)
+	require.NoError(s.T(), err)
+	datas := Blocks[*DummyData](config)
+	require.Len(s.T(), datas, 1)
+	s.Equal("dev", datas[0].Tags["key"])
+	s.Equal("/dev/main.hcl", datas[0].HclBlock().Range().Filename)
+	s.False(config.ValidBlockAddress("data.dummy.bar"))
+	s.False(config.ValidBlockAddress("local.b"))
+	s.False(config.ValidBlockAddress("var.v"))
+	locals := config.EvalContext().Variables["local"]
+	s.Equal(cty.StringVal("base_a"), locals.GetAttr("a"))
+	s.Equal(cty.StringVal("dev_c"), locals.GetAttr("c"))
+	for _, l := range Blocks[*LocalBlock](config) {
+		if l.Name() == "a" {
+			s.Equal("/base/main.hcl", l.HclBlock().Range().Filename)
+		}
+	}
+}
+
+func (s *layerSuite) TestLoadLayeredHclBlocks_RemoveNonExistentBlockShouldReturnError() {
+	s.dummyFsWithFiles(map[string]string{
+		"/base/main.hcl": `locals {
+  a = "a"
+}
+`,
+		"/dev/main.hcl": `removed {
+  from = data.dummy.missing
+}
+`,
+	})
+	_, err := LoadLayeredHclBlocks(false, []string{"/base", "/dev"})
+	require.NotNil(s.T(), err)
+	s.Contains(err.Error(), "/dev/main.hcl:1,")
+	s.Contains(err.Error(), "targets non-existent block data.dummy.missing")
+}
+
+func (s *layerSuite) TestLoadLayeredHclBlocks_RemovedWithoutFromShouldReturnError() {
+	s.dummyFsWithFiles(map[string]string{
+		"/base/main.hcl": `locals {
+  a = "a"
+}
+`,
+		"/dev/main.hcl": `removed {
+}
+`,
+	})
+	_, err := LoadLayeredHclBlocks(false, []string{"/base", "/dev"})
+	require.NotNil(s.T(), err)
+	s.Contains(err.Error(), "Missing `from`")
+}
+
+func (s *layerSuite) TestNewBasicConfigFromArgs_BasedirDefaultsToLastLayer() {
+	c := NewBasicConfigFromArgs(NewBaseConfigArgs{
+		Layers: []string{"/base", "/dev"},
+	})
+	s.Equal("/dev", c.basedir)
+	s.Equal([]string{"/base", "/dev"}, c.Layers())
+	c = NewBasicConfigFromArgs(NewBaseConfigArgs{
+		Basedir: "/base",
+	})
+	s.Equal([]string{"/base"}, c.Layers())
+}
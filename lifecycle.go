@@ -0,0 +1,245 @@
+package golden
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/zclconf/go-cty/cty"
+)
+
+const lifecycleBlockType = "lifecycle"
+
+// Lifecycle is parsed from the `lifecycle` meta nested block, all arguments must be static.
+// Except IgnoreChanges, the knobs take effect only when state is enabled, since they need to know what the last apply did.
+type Lifecycle struct {
+	// PreventDestroy makes any destroy of the block an error, including replacements and removing the block from configuration.
+	PreventDestroy bool
+	// IgnoreChanges are attribute paths like `tags` or `tags.env` whose values are kept from prior state.
+	IgnoreChanges []hcl.Traversal
+	// IgnoreAllChanges is set by `ignore_changes = all`.
+	IgnoreAllChanges bool
+	// CreateBeforeDestroy applies the new block before destroying the old one on replacement and removal.
+	CreateBeforeDestroy bool
+	// ReplaceTriggeredBy are references to blocks, the block is replaced when any of them is created, updated or replaced.
+	ReplaceTriggeredBy []hcl.Traversal
+}
+
+// blockLifecycle returns an empty Lifecycle for blocks without BaseBlock.
+func blockLifecycle(b Block) (*Lifecycle, error) {
+	if l, ok := b.(interface{ lifecycle() (*Lifecycle, error) }); ok {
+		return l.lifecycle()
+	}
+	return &Lifecycle{}, nil
+}
+
+func (bb *BaseBlock) lifecycle() (*Lifecycle, error) {
+	if bb.lifecycleParsed {
+		return bb.parsedLifecycle, bb.lifecycleErr
+	}
+	bb.lifecycleParsed = true
+	bb.parsedLifecycle, bb.lifecycleErr = parseLifecycle(bb.HclBlock())
+	return bb.parsedLifecycle, bb.lifecycleErr
+}
+
+func parseLifecycle(hb *HclBlock) (*Lifecycle, error) {
+	lc := &Lifecycle{}
+	if hb.Body == nil {
+		return lc, nil
+	}
+	var found *hclsyntax.Block
+	for _, nb := range hb.Body.Blocks {
+		if nb.Type != lifecycleBlockType {
+			continue
+		}
+		if found != nil {
+			return nil, fmt.Errorf("duplicate `lifecycle` block, %s", nb.Range().String())
+		}
+		found = nb
+	}
+	if found == nil {
+		return lc, nil
+	}
+	for name, attr := range found.Body.Attributes {
+		var err error
+		switch name {
+		case "prevent_destroy":
+			lc.PreventDestroy, err = staticBool(attr)
+		case "create_before_destroy":
+			lc.CreateBeforeDestroy, err = staticBool(attr)
+		case "ignore_changes":
+			if hcl.ExprAsKeyword(attr.Expr) == "all" {
+				lc.IgnoreAllChanges = true
+				continue
+			}
+			lc.IgnoreChanges, err = staticTraversals(attr)
+		case "replace_triggered_by":
+			lc.ReplaceTriggeredBy, err = staticTraversals(attr)
+		default:
+			err = fmt.Errorf("unsupported argument `%s` in `lifecycle`, %s", name, attr.SrcRange.String())
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return lc, nil
+}
+
+func staticBool(attr *hclsyntax.Attribute) (bool, error) {
+	v, diag := attr.Expr.Value(nil)
+	if diag.HasErrors() || v.Type() != cty.Bool || v.IsNull() {
+		return false, fmt.Errorf("`%s` in `lifecycle` must be a literal bool, %s", attr.Name, attr.SrcRange.String())
+	}
+	return v.True(), nil
+}
+
+func staticTraversals(attr *hclsyntax.Attribute) ([]hcl.Traversal, error) {
+	exprs, diag := hcl.ExprList(attr.Expr)
+	if diag.HasErrors() {
+		return nil, fmt.Errorf("`%s` in `lifecycle` must be a list of references, %s", attr.Name, attr.SrcRange.String())
+	}
+	var r []hcl.Traversal
+	for _, expr := range exprs {
+		t, diag := hcl.AbsTraversalForExpr(expr)
+		if diag.HasErrors() {
+			return nil, fmt.Errorf("`%s` in `lifecycle` must be a list of references, %s", attr.Name, expr.Range().String())
+		}
+		r = append(r, t)
+	}
+	return r, nil
+}
+
+// applyIgnoreChanges replaces ignored attributes of b with values from prior state.
+func applyIgnoreChanges(b Block) error {
+	lc, err := blockLifecycle(b)
+	if err != nil || (!lc.IgnoreAllChanges && len(lc.IgnoreChanges) == 0) {
+		return err
+	}
+	prior, ok := PriorState(b)
+	if !ok {
+		return nil
+	}
+	current := Value(b)
+	ignored := make(map[string]cty.Value)
+	if lc.IgnoreAllChanges {
+		ignored = prior
+	}
+	for _, t := range lc.IgnoreChanges {
+		name := t.RootName()
+		priorValue, ok := prior[name]
+		if !ok {
+			continue
+		}
+		if _, ok = ignored[name]; !ok {
+			ignored[name] = current[name]
+		}
+		path, err := traversalToPath(ignored[name].Type(), t[1:])
+		if err != nil {
+			return fmt.Errorf("invalid `ignore_changes` %s: %+v", traversalString(t), err)
+		}
+		priorAtPath, err := path.Apply(priorValue)
+		if err != nil {
+			// the path didn't exist at last apply, there's nothing to keep.
+			continue
+		}
+		if ignored[name], err = setAtPath(ignored[name], path, priorAtPath); err != nil {
+			return fmt.Errorf("invalid `ignore_changes` %s: %+v", traversalString(t), err)
+		}
+	}
+	return restoreValues(b, ignored)
+}
+
+// traversalToPath converts a relative traversal to a cty.Path, attribute access on maps becomes index.
+func traversalToPath(t cty.Type, rel hcl.Traversal) (cty.Path, error) {
+	var path cty.Path
+	for _, step := range rel {
+		var key cty.Value
+		switch s := step.(type) {
+		case hcl.TraverseAttr:
+			key = cty.StringVal(s.Name)
+		case hcl.TraverseIndex:
+			key = s.Key
+		default:
+			return nil, fmt.Errorf("unsupported step %T", step)
+		}
+		switch {
+		case t.IsObjectType() && key.Type() == cty.String:
+			if !t.HasAttribute(key.AsString()) {
+				return nil, fmt.Errorf("no attribute `%s`", key.AsString())
+			}
+			path = path.GetAttr(key.AsString())
+			t = t.AttributeType(key.AsString())
+		case t.IsMapType() || t.IsListType():
+			path = path.Index(key)
+			t = t.ElementType()
+		default:
+			return nil, fmt.Errorf("cannot access %s on %s", CtyValueToString(key), t.FriendlyName())
+		}
+	}
+	return path, nil
+}
+
+func setAtPath(v cty.Value, path cty.Path, nv cty.Value) (cty.Value, error) {
+	if len(path) == 0 {
+		return nv, nil
+	}
+	if v.IsNull() || !v.IsKnown() {
+		return v, nil
+	}
+	switch s := path[0].(type) {
+	case cty.GetAttrStep:
+		m := v.AsValueMap()
+		child, err := setAtPath(m[s.Name], path[1:], nv)
+		if err != nil {
+			return cty.NilVal, err
+		}
+		m[s.Name] = child
+		return cty.ObjectVal(m), nil
+	case cty.IndexStep:
+		if v.Type().IsMapType() {
+			m := v.AsValueMap()
+			if m == nil {
+				m = make(map[string]cty.Value)
+			}
+			key := s.Key.AsString()
+			child, err := setAtPath(m[key], path[1:], nv)
+			if err != nil {
+				return cty.NilVal, err
+			}
+			m[key] = child
+			return cty.MapVal(m), nil
+		}
+		elems := v.AsValueSlice()
+		i, _ := s.Key.AsBigFloat().Int64()
+		if i < 0 || int(i) >= len(elems) {
+			return v, nil
+		}
+		child, err := setAtPath(elems[i], path[1:], nv)
+		if err != nil {
+			return cty.NilVal, err
+		}
+		elems[i] = child
+		return cty.ListVal(elems), nil
+	}
+	return cty.NilVal, fmt.Errorf("unsupported path step %T", path[0])
+}
+
+// replaceTriggered returns true if any reference in `replace_triggered_by` has one of triggering actions.
+func replaceTriggered(lc *Lifecycle, actions map[string]StateAction) bool {
+	for _, t := range lc.ReplaceTriggeredBy {
+		// the reference could be a block, a `for_each` instance, or an attribute of them.
+		for i := 1; i <= len(t); i++ {
+			address := traversalString(t[:i])
+			for a, action := range actions {
+				if action != StateActionCreate && action != StateActionUpdate && action != StateActionReplace {
+					continue
+				}
+				if a == address || strings.HasPrefix(a, address+"[") {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,202 @@
+package golden
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type lifecycleSuite struct {
+	suite.Suite
+	*testBase
+}
+
+func TestLifecycleSuite(t *testing.T) {
+	suite.Run(t, new(lifecycleSuite))
+}
+
+func (s *lifecycleSuite) SetupTest() {
+	s.testBase = newTestBase()
+	resetDestroyRecords()
+}
+
+func (s *lifecycleSuite) TearDownTest() {
+	s.teardown()
+}
+
+func (s *lifecycleSuite) plan(config string) (*DummyConfig, error) {
+	require.NoError(s.T(), afero.WriteFile(s.fs, "/main.hcl", []byte(config), 0644))
+	hclBlocks, err := loadHclBlocks(false, "/")
+	require.NoError(s.T(), err)
+	c := &DummyConfig{
+		BaseConfig: NewBasicConfigFromArgs(NewBaseConfigArgs{
+			Basedir:         "/",
+			DslFullName:     "faketerraform",
+			DslAbbreviation: "ft",
+			StatePath:       "/state.json",
+		}),
+	}
+	require.NoError(s.T(), InitConfig(c, hclBlocks))
+	return c, c.RunPlan()
+}
+
+func (s *lifecycleSuite) apply(config string) error {
+	c, err := s.plan(config)
+	require.NoError(s.T(), err)
+	return c.RunApply()
+}
+
+func (s *lifecycleSuite) TestLifecycle_InvalidArguments() {
+	cases := map[string]string{
+		"unsupported argument `foo` in `lifecycle`": `lifecycle {
+    foo = true
+  }`,
+		"`prevent_destroy` in `lifecycle` must be a literal bool": `lifecycle {
+    prevent_destroy = local.x
+  }`,
+		"`ignore_changes` in `lifecycle` must be a list of references": `lifecycle {
+    ignore_changes = ["tags"]
+  }`,
+		"duplicate `lifecycle` block": `lifecycle {
+  }
+  lifecycle {
+  }`,
+	}
+	for want, lifecycle := range cases {
+		s.Run(want, func() {
+			_, err := s.plan(`locals {
+  x = true
+}
+
+resource "dummy" "foo" {
+  ` + lifecycle + `
+}
+`)
+			s.ErrorContains(err, want)
+		})
+	}
+}
+
+func (s *lifecycleSuite) TestLifecycle_IgnoreChanges() {
+	require.NoError(s.T(), s.apply(`resource "dummy" "foo" {
+  tags = {
+    env   = "dev"
+    owner = "alice"
+  }
+}
+`))
+	c, err := s.plan(`resource "dummy" "foo" {
+  tags = {
+    env   = "prod"
+    owner = "bob"
+  }
+  lifecycle {
+    ignore_changes = [tags.env]
+  }
+}
+`)
+	require.NoError(s.T(), err)
+	s.Equal(map[string]string{"env": "dev", "owner": "bob"}, Blocks[*DummyResource](c)[0].Tags)
+	s.Equal([]StateChange{{Address: "resource.dummy.foo", Action: StateActionUpdate}}, c.StateChanges())
+
+	c, err = s.plan(`resource "dummy" "foo" {
+  tags = {
+    env = "test"
+  }
+  lifecycle {
+    ignore_changes = all
+  }
+}
+`)
+	require.NoError(s.T(), err)
+	s.Equal(map[string]string{"env": "dev", "owner": "alice"}, Blocks[*DummyResource](c)[0].Tags)
+	s.Equal([]StateChange{{Address: "resource.dummy.foo", Action: StateActionNoOp}}, c.StateChanges())
+}
+
+func (s *lifecycleSuite) TestLifecycle_PreventDestroy() {
+	require.NoError(s.T(), s.apply(`resource "destroyable" "foo" {
+  tags = {
+    name = "foo"
+  }
+  lifecycle {
+    prevent_destroy = true
+  }
+}
+`))
+	err := s.apply(`resource "dummy" "other" {
+}
+`)
+	s.ErrorContains(err, "resource.destroyable.foo has `lifecycle.prevent_destroy` set and cannot be destroyed")
+	s.Empty(destroyed)
+	c, err := s.plan(`resource "dummy" "other" {
+}
+`)
+	require.NoError(s.T(), err)
+	s.Contains(c.StateChanges(), StateChange{Address: "resource.destroyable.foo", Action: StateActionDelete})
+}
+
+const replaceTriggeredConfig = `resource "dummy" "trigger" {
+  tags = {
+    version = "%s"
+  }
+}
+
+resource "destroyable" "foo" {
+  tags = {
+    name = "foo"
+  }
+  lifecycle {
+    replace_triggered_by  = [resource.dummy.trigger]
+    create_before_destroy = %t
+  }
+}
+`
+
+func (s *lifecycleSuite) TestLifecycle_ReplaceTriggeredBy() {
+	for _, createBeforeDestroy := range []bool{false, true} {
+		s.Run(fmt.Sprintf("create_before_destroy=%t", createBeforeDestroy), func() {
+			require.NoError(s.T(), afero.WriteFile(s.fs, "/state.json", []byte(`{"format_version":"1.0","blocks":{}}`), 0644))
+			require.NoError(s.T(), s.apply(fmt.Sprintf(replaceTriggeredConfig, "1", createBeforeDestroy)))
+			c, err := s.plan(fmt.Sprintf(replaceTriggeredConfig, "1", createBeforeDestroy))
+			require.NoError(s.T(), err)
+			s.Contains(c.StateChanges(), StateChange{Address: "resource.destroyable.foo", Action: StateActionNoOp})
+
+			resetDestroyRecords()
+			c, err = s.plan(fmt.Sprintf(replaceTriggeredConfig, "2", createBeforeDestroy))
+			require.NoError(s.T(), err)
+			s.Equal([]StateChange{
+				{Address: "resource.destroyable.foo", Action: StateActionReplace},
+				{Address: "resource.dummy.trigger", Action: StateActionUpdate},
+			}, c.StateChanges())
+			require.NoError(s.T(), c.RunApply())
+			if createBeforeDestroy {
+				s.Equal([]string{"apply foo", "destroy foo"}, destroyEvents)
+			} else {
+				s.Equal([]string{"destroy foo", "apply foo"}, destroyEvents)
+			}
+		})
+	}
+}
+
+func (s *lifecycleSuite) TestLifecycle_CreateBeforeDestroyOnRemoval() {
+	require.NoError(s.T(), s.apply(`resource "destroyable" "old" {
+  tags = {
+    name = "old"
+  }
+  lifecycle {
+    create_before_destroy = true
+  }
+}
+`))
+	resetDestroyRecords()
+	require.NoError(s.T(), s.apply(`resource "destroyable" "new" {
+  tags = {
+    name = "new"
+  }
+}
+`))
+	s.Equal([]string{"apply new", "destroy old"}, destroyEvents)
+}
@@ -0,0 +1,41 @@
+package golden
+
+import (
+	"log/slog"
+
+	"github.com/zclconf/go-cty/cty"
+)
+
+const redactedValue = "(sensitive value)"
+
+var discardLogger = slog.New(slog.DiscardHandler)
+
+// Logger returns the logger set by NewBaseConfigArgs.Logger, it discards all records by default.
+// Records of configs loaded by `module` blocks have a `module` attribute like `module.child`.
+func (c *BaseConfig) Logger() *slog.Logger {
+	if c.logger == nil {
+		return discardLogger
+	}
+	if c.modulePath != "" {
+		return c.logger.With("module", c.modulePath)
+	}
+	return c.logger
+}
+
+func configLogger(c Config) *slog.Logger {
+	if bc, ok := c.(interface{ baseConfig() *BaseConfig }); ok {
+		return bc.baseConfig().Logger()
+	}
+	return discardLogger
+}
+
+// logValue renders v for logs, values of sensitive variables are redacted.
+func logValue(v cty.Value, sensitive bool) string {
+	if sensitive {
+		return redactedValue
+	}
+	if v == cty.NilVal {
+		return "null"
+	}
+	return renderValue(v)
+}
@@ -0,0 +1,118 @@
+package golden
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type loggingSuite struct {
+	suite.Suite
+	*testBase
+	logs *bytes.Buffer
+}
+
+func TestLoggingSuite(t *testing.T) {
+	suite.Run(t, new(loggingSuite))
+}
+
+func (s *loggingSuite) SetupTest() {
+	s.testBase = newTestBase()
+	s.logs = new(bytes.Buffer)
+}
+
+func (s *loggingSuite) TearDownTest() {
+	s.teardown()
+}
+
+func (s *loggingSuite) config(logger *slog.Logger, config string) (*DummyConfig, error) {
+	s.dummyFsWithFiles(map[string]string{
+		"/main.hcl": config,
+	})
+	hclBlocks, err := loadHclBlocks(false, "/")
+	require.NoError(s.T(), err)
+	c := &DummyConfig{
+		BaseConfig: NewBasicConfigFromArgs(NewBaseConfigArgs{
+			Basedir:         "/",
+			DslFullName:     "faketerraform",
+			DslAbbreviation: "ft",
+			Ctx:             context.Background(),
+			Logger:          logger,
+		}),
+	}
+	return c, InitConfig(c, hclBlocks)
+}
+
+func (s *loggingSuite) debugLogger() *slog.Logger {
+	return slog.New(slog.NewTextHandler(s.logs, &slog.HandlerOptions{Level: slog.LevelDebug}))
+}
+
+func (s *loggingSuite) TestLogging_DebugRecords() {
+	c, err := s.config(s.debugLogger(), `variable "region" {
+  default = "eastus"
+}
+
+variable "password" {
+  default   = "p@ssw0rd"
+  sensitive = true
+}
+
+resource "dummy" "foo" {
+  for_each = toset([var.region])
+  tags = {
+    region   = each.value
+    password = var.password
+  }
+}
+`)
+	require.NoError(s.T(), err)
+	require.NoError(s.T(), c.RunPlan())
+	logs := s.logs.String()
+	s.Contains(logs, `msg="resolved variable" address=var.region source="default value /main.hcl:2,3" value="\"eastus\""`)
+	s.Contains(logs, `msg="resolved variable" address=var.password`)
+	s.Contains(logs, `value="(sensitive value)"`)
+	s.NotContains(logs, "p@ssw0rd")
+	s.Contains(logs, `msg="added dag edge" from=var.region to=resource.dummy.foo`)
+	s.Contains(logs, `msg="expanded block by for_each" address=resource.dummy.foo instances=[resource.dummy.foo[eastus]]`)
+	s.Contains(logs, `msg="executing block" address=resource.dummy.foo[eastus] phase=plan`)
+	s.Contains(logs, `msg="block executed" address=resource.dummy.foo[eastus] phase=plan duration=`)
+	for _, v := range Blocks[*VariableBlock](c) {
+		s.Equal(v.Name() == "password", v.Sensitive, v.Name())
+	}
+}
+
+func (s *loggingSuite) TestLogging_FailedBlock() {
+	c, err := s.config(s.debugLogger(), `data "dummy" "foo" {
+  precondition {
+    condition     = false
+    error_message = "foo is not ready"
+  }
+}
+`)
+	require.NoError(s.T(), err)
+	s.NotNil(c.RunPlan())
+	s.Contains(s.logs.String(), `msg="block failed" address=data.dummy.foo phase=plan`)
+}
+
+func (s *loggingSuite) TestLogging_SilentByDefault() {
+	c, err := s.config(nil, `variable "region" {
+  default = "eastus"
+}
+`)
+	require.NoError(s.T(), err)
+	require.NoError(s.T(), c.RunPlan())
+	s.Same(discardLogger, c.Logger())
+}
+
+func (s *loggingSuite) TestLogging_SensitiveMustBeBool() {
+	_, err := s.config(nil, `variable "password" {
+  default   = "p@ssw0rd"
+  sensitive = "yes"
+}
+`)
+	s.ErrorContains(err, "incorrect type for `sensitive`")
+}
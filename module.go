@@ -0,0 +1,219 @@
+package golden
+
+import (
+	"fmt"
+	"path/filepath"
+	"slices"
+	"sort"
+
+	"github.com/hashicorp/go-multierror"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/zclconf/go-cty/cty"
+)
+
+var _ SingleValueBlock = &ModuleBlock{}
+var _ PlanBlock = &ModuleBlock{}
+var _ ApplyBlock = &ModuleBlock{}
+var _ CustomDecode = &ModuleBlock{}
+
+// ModuleBlock loads the directory of `source` into a child config, other attributes are passed to the child config as variables.
+// Outputs of the child config are exposed as `module.<name>.<output>`.
+type ModuleBlock struct {
+	*BaseBlock
+	Source    string
+	arguments map[string]VariableValueRead
+	child     *BaseConfig
+}
+
+func (m *ModuleBlock) Decode(hb *HclBlock, ctx *hcl.EvalContext) error {
+	sourceAttr, ok := hb.Body.Attributes["source"]
+	if !ok {
+		return fmt.Errorf("`source` is required for %s", m.Address())
+	}
+	source, diag := sourceAttr.Expr.Value(nil)
+	if diag.HasErrors() {
+		return diag
+	}
+	if source.Type() != cty.String || source.IsNull() {
+		return fmt.Errorf("`source` of %s must be a string literal, %s", m.Address(), sourceAttr.SrcRange.String())
+	}
+	m.Source = source.AsString()
+	m.arguments = make(map[string]VariableValueRead)
+	for n, attr := range hb.Body.Attributes {
+		if n == "source" || MetaAttributeNames.Contains(n) {
+			continue
+		}
+		value, diag := attr.Expr.Value(ctx)
+		if diag.HasErrors() {
+			return diag
+		}
+		m.arguments[n] = NewVariableValueRead(n, &value, nil).withSource(VariableValueSource{
+			Type:     VariableValueSourceModuleArgument,
+			FileName: attr.SrcRange.Filename,
+			Range:    attr.SrcRange,
+		})
+	}
+	return nil
+}
+
+func (m *ModuleBlock) ExecuteDuringPlan() error {
+	child, err := m.loadChild()
+	if err != nil {
+		return err
+	}
+	if err = child.RunPlan(); err != nil {
+		return fmt.Errorf("%s: %+v", m.Address(), err)
+	}
+	m.child = child
+	return nil
+}
+
+// loadChild loads and initializes the child config, it's not planned yet.
+func (m *ModuleBlock) loadChild() (*BaseConfig, error) {
+	parent, ok := m.c.(interface{ baseConfig() *BaseConfig })
+	if !ok {
+		return nil, fmt.Errorf("%s: module is not supported by %T", m.Address(), m.c)
+	}
+	p := parent.baseConfig()
+	dir := m.Dir()
+	absDir, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, err
+	}
+	if slices.Contains(p.moduleDirs, absDir) {
+		return nil, fmt.Errorf("%s: recursive module %s", m.Address(), m.Source)
+	}
+	hclBlocks, err := LoadHclBlocks(false, dir)
+	if err != nil {
+		return nil, fmt.Errorf("%s: cannot load %s: %+v", m.Address(), m.Source, err)
+	}
+	if err = m.checkArguments(hclBlocks); err != nil {
+		return nil, err
+	}
+	child := NewBasicConfig(dir, p.dslFullName, p.dslAbbreviation, nil, nil, p.ctx)
+	child.moduleArguments = m.arguments
+	child.rootDir = p.rootDir
+	child.workspace = p.workspace
+	child.moduleDirs = append(slices.Clone(p.moduleDirs), absDir)
+	child.modulePath = moduleAddress(p, m.Address())
+	child.priorState = p.priorState
+	child.ignoreUnknownVariables = p.ignoreUnknownVariables
+	child.OverrideFunctions = p.OverrideFunctions
+	child.registeredFunctions = p.registeredFunctions
+	child.builtinOverrides = p.builtinOverrides
+	child.retryPolicy = p.retryPolicy
+	child.hooks = p.hooks
+	child.tracer = p.tracer
+	child.meter = p.meter
+	child.logger = p.logger
+	child.spanCtx = p.spanCtx
+	if p.sandbox != nil {
+		child.EnableSandbox(p.sandboxRoots()...)
+	}
+	if err = InitConfig(child, hclBlocks); err != nil {
+		return nil, fmt.Errorf("%s: %+v", m.Address(), err)
+	}
+	return child, nil
+}
+
+func (m *ModuleBlock) Apply() error {
+	if m.child == nil {
+		return nil
+	}
+	if parent, ok := m.c.(interface{ baseConfig() *BaseConfig }); ok {
+		m.child.spanCtx = parent.baseConfig().spanCtx
+	}
+	if err := m.child.RunApply(); err != nil {
+		return fmt.Errorf("%s: %+v", m.Address(), err)
+	}
+	return nil
+}
+
+// checkArguments ensures every argument matches a variable of the module, and every variable without default has an argument.
+func (m *ModuleBlock) checkArguments(hclBlocks []*HclBlock) error {
+	variables := make(map[string]bool)
+	for _, hb := range hclBlocks {
+		if hb.Type != "variable" {
+			continue
+		}
+		_, hasDefault := hb.Body.Attributes["default"]
+		variables[hb.Labels[1]] = hasDefault
+	}
+	var err error
+	var names []string
+	for n := range m.arguments {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	for _, n := range names {
+		if _, ok := variables[n]; !ok {
+			err = multierror.Append(err, fmt.Errorf("%s: unsupported argument `%s`, no variable declared in %s, %s", m.Address(), n, m.Source, m.arguments[n].Source.location()))
+		}
+	}
+	names = nil
+	for n := range variables {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	for _, n := range names {
+		if _, ok := m.arguments[n]; !ok && !variables[n] {
+			err = multierror.Append(err, fmt.Errorf("%s: missing required argument `%s`, %s", m.Address(), n, m.HclBlock().Range().String()))
+		}
+	}
+	return err
+}
+
+// FullAddress returns the address of b prefixed by the path of its module, like `module.child.resource.dummy.bar`,
+// it's the same as Address for blocks of the root config. Hooks, events, spans and state use full addresses.
+func FullAddress(b Block) string {
+	return moduleAddress(b.Config(), b.Address())
+}
+
+func moduleAddress(c Config, address string) string {
+	bc, ok := c.(interface{ baseConfig() *BaseConfig })
+	if !ok || bc.baseConfig().modulePath == "" {
+		return address
+	}
+	return bc.baseConfig().modulePath + "." + address
+}
+
+// Dir returns the directory of the module, relative `source` is resolved from the directory of the file that declares the module.
+func (m *ModuleBlock) Dir() string {
+	if filepath.IsAbs(m.Source) {
+		return m.Source
+	}
+	return filepath.Join(filepath.Dir(m.HclBlock().Range().Filename), m.Source)
+}
+
+// ModuleConfig returns the child config, it's nil before the module has been planned.
+func (m *ModuleBlock) ModuleConfig() Config {
+	if m.child == nil {
+		return nil
+	}
+	return m.child
+}
+
+func (m *ModuleBlock) Value() cty.Value {
+	if m.child == nil {
+		return cty.EmptyObjectVal
+	}
+	var outputs []SingleValueBlock
+	for _, o := range Blocks[*OutputBlock](m.child) {
+		outputs = append(outputs, o)
+	}
+	return SingleValues(outputs)
+}
+
+func (m *ModuleBlock) Type() string {
+	return ""
+}
+
+func (m *ModuleBlock) BlockType() string {
+	return "module"
+}
+
+func (m *ModuleBlock) AddressLength() int { return 2 }
+
+func (m *ModuleBlock) CanExecutePrePlan() bool {
+	return false
+}
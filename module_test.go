@@ -0,0 +1,208 @@
+package golden
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"github.com/zclconf/go-cty/cty"
+)
+
+type moduleSuite struct {
+	suite.Suite
+	*testBase
+}
+
+func TestModuleSuite(t *testing.T) {
+	suite.Run(t, new(moduleSuite))
+}
+
+func (s *moduleSuite) SetupTest() {
+	s.testBase = newTestBase()
+}
+
+func (s *moduleSuite) TearDownTest() {
+	s.teardown()
+}
+
+const greetingModule = `variable "name" {
+  type = string
+}
+
+variable "punctuation" {
+  default = "!"
+}
+
+locals {
+  greeting = "hello ${var.name}${var.punctuation}"
+}
+
+output "greeting" {
+  value = local.greeting
+}
+`
+
+func (s *moduleSuite) TestModule_OutputShouldBeReferencedByParent() {
+	s.dummyFsWithFiles(map[string]string{
+		"/main.hcl": `data "dummy" foo {
+  data = {
+    name = "world"
+  }
+}
+
+module "greeting" {
+  source = "./greeting"
+  name   = data.dummy.foo.data["name"]
+}
+
+data "dummy" bar {
+  data = {
+    greeting = module.greeting.greeting
+  }
+}
+`,
+		"/greeting/main.hcl": greetingModule,
+	})
+	config, err := BuildDummyConfig("/", "/", nil, nil)
+	require.NoError(s.T(), err)
+	_, err = RunDummyPlan(config)
+	require.NoError(s.T(), err)
+	bar := s.dummyData(config, "data.dummy.bar")
+	s.Equal("hello world!", bar.Tags["greeting"])
+	modules := Blocks[*ModuleBlock](config)
+	require.Len(s.T(), modules, 1)
+	child := modules[0].ModuleConfig()
+	require.NotNil(s.T(), child)
+	s.True(child.ValidBlockAddress("local.greeting"))
+	s.False(config.ValidBlockAddress("local.greeting"))
+	explanation, err := child.(*BaseConfig).ExplainVariable("name")
+	require.NoError(s.T(), err)
+	s.Equal(VariableValueSourceModuleArgument, explanation.Source.Type)
+	s.Equal("/main.hcl", explanation.Source.FileName)
+}
+
+func (s *moduleSuite) TestModule_ForEach() {
+	s.dummyFsWithFiles(map[string]string{
+		"/main.hcl": `module "greeting" {
+  for_each    = toset(["alice", "bob"])
+  source      = "./greeting"
+  name        = each.value
+  punctuation = "?"
+}
+
+data "dummy" bar {
+  data = {
+    alice = module.greeting["alice"].greeting
+    bob   = module.greeting["bob"].greeting
+  }
+}
+`,
+		"/greeting/main.hcl": greetingModule,
+	})
+	config, err := BuildDummyConfig("/", "/", nil, nil)
+	require.NoError(s.T(), err)
+	_, err = RunDummyPlan(config)
+	require.NoError(s.T(), err)
+	bar := s.dummyData(config, "data.dummy.bar")
+	s.Equal("hello alice?", bar.Tags["alice"])
+	s.Equal("hello bob?", bar.Tags["bob"])
+}
+
+func (s *moduleSuite) TestModule_NestedModule() {
+	s.dummyFsWithFiles(map[string]string{
+		"/main.hcl": `module "outer" {
+  source = "./outer"
+}
+`,
+		"/outer/main.hcl": `module "inner" {
+  source = "../greeting"
+  name   = "nested"
+}
+
+output "greeting" {
+  value = module.inner.greeting
+}
+`,
+		"/greeting/main.hcl": greetingModule,
+	})
+	config, err := BuildDummyConfig("/", "/", nil, nil)
+	require.NoError(s.T(), err)
+	_, err = RunDummyPlan(config)
+	require.NoError(s.T(), err)
+	outer := Blocks[*ModuleBlock](config)[0]
+	s.Equal(cty.ObjectVal(map[string]cty.Value{
+		"greeting": cty.StringVal("hello nested!"),
+	}), outer.Value())
+}
+
+func (s *moduleSuite) TestModule_InvalidArguments() {
+	s.dummyFsWithFiles(map[string]string{
+		"/main.hcl": `module "greeting" {
+  source  = "./greeting"
+  unknown = "value"
+}
+`,
+		"/greeting/main.hcl": greetingModule,
+	})
+	config, err := BuildDummyConfig("/", "/", nil, nil)
+	require.NoError(s.T(), err)
+	_, err = RunDummyPlan(config)
+	require.NotNil(s.T(), err)
+	s.Contains(err.Error(), "unsupported argument `unknown`")
+	s.Contains(err.Error(), "missing required argument `name`")
+}
+
+func (s *moduleSuite) TestModule_RecursiveModule() {
+	s.dummyFsWithFiles(map[string]string{
+		"/main.hcl": `module "self" {
+  source = "./self"
+}
+`,
+		"/self/main.hcl": `module "self" {
+  source = "."
+}
+`,
+	})
+	config, err := BuildDummyConfig("/", "/", nil, nil)
+	require.NoError(s.T(), err)
+	_, err = RunDummyPlan(config)
+	require.NotNil(s.T(), err)
+	s.Contains(err.Error(), "recursive module")
+}
+
+func (s *moduleSuite) TestModule_ApplyShouldApplyChildBlocks() {
+	s.dummyFsWithFiles(map[string]string{
+		"/main.hcl": `module "child" {
+  source = "./child"
+}
+`,
+		"/child/main.hcl": `resource "dummy" foo {
+  tags = {
+    env = "dev"
+  }
+  postcondition {
+    condition     = self.tags["env"] == "prod"
+    error_message = "env must be prod"
+  }
+}
+`,
+	})
+	config, err := BuildDummyConfig("/", "/", nil, nil)
+	require.NoError(s.T(), err)
+	_, err = RunDummyPlan(config)
+	require.NoError(s.T(), err)
+	err = config.RunApply()
+	require.NotNil(s.T(), err)
+	s.Contains(err.Error(), "module.child")
+	s.Contains(err.Error(), "env must be prod")
+}
+
+func (s *moduleSuite) dummyData(c Config, address string) *DummyData {
+	for _, d := range Blocks[*DummyData](c) {
+		if d.Address() == address {
+			return d
+		}
+	}
+	s.FailNow("cannot find " + address)
+	return nil
+}
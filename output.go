@@ -0,0 +1,42 @@
+package golden
+
+import (
+	"github.com/zclconf/go-cty/cty"
+)
+
+var _ SingleValueBlock = &OutputBlock{}
+var _ PlanBlock = &OutputBlock{}
+
+// OutputBlock exposes a value of a module to its caller as `module.<name>.<output>`.
+type OutputBlock struct {
+	*BaseBlock
+	OutputValue cty.Value `hcl:"value"`
+	Description string    `hcl:"description,optional"`
+}
+
+func (o *OutputBlock) ExecuteDuringPlan() error {
+	value, diag := o.HclBlock().Body.Attributes["value"].Expr.Value(o.EvalContext())
+	if diag.HasErrors() {
+		return diag
+	}
+	o.OutputValue = value
+	return nil
+}
+
+func (o *OutputBlock) Value() cty.Value {
+	return o.OutputValue
+}
+
+func (o *OutputBlock) Type() string {
+	return ""
+}
+
+func (o *OutputBlock) BlockType() string {
+	return "output"
+}
+
+func (o *OutputBlock) AddressLength() int { return 2 }
+
+func (o *OutputBlock) CanExecutePrePlan() bool {
+	return false
+}
@@ -0,0 +1,79 @@
+package golden
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/hashicorp/go-multierror"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+)
+
+// isOverrideFile returns true for `override.hcl` and `*_override.hcl`.
+func isOverrideFile(fileName string) bool {
+	n := strings.TrimSuffix(filepath.Base(fileName), ".hcl")
+	return n == "override" || strings.HasSuffix(n, "_override")
+}
+
+// applyOverrides merges override blocks into same-addressed blocks in order, like Terraform:
+// attributes in override block replace the base ones, nested blocks replace all base nested blocks with the same type.
+func applyOverrides(blocks, overrides []*HclBlock) ([]*HclBlock, error) {
+	indexes := make(map[string]int)
+	for i, b := range blocks {
+		indexes[blockAddress(b)] = i
+	}
+	var err error
+	for _, o := range overrides {
+		address := blockAddress(o)
+		i, ok := indexes[address]
+		if !ok {
+			err = multierror.Append(err, fmt.Errorf("override %s %s targets non-existent block", address, o.Range().String()))
+			continue
+		}
+		blocks[i] = mergeOverrideBlock(blocks[i], o)
+	}
+	return blocks, err
+}
+
+func mergeOverrideBlock(base, override *HclBlock) *HclBlock {
+	body := *base.Body
+	body.Attributes = make(hclsyntax.Attributes)
+	for n, attr := range base.Body.Attributes {
+		body.Attributes[n] = attr
+	}
+	wb := base.wb
+	for n, attr := range override.Body.Attributes {
+		body.Attributes[n] = attr
+		wb.Body().SetAttributeRaw(n, override.wb.Body().Attributes()[n].Expr().BuildTokens(nil))
+	}
+	overriddenTypes := make(map[string]struct{})
+	for _, nb := range override.Body.Blocks {
+		overriddenTypes[nestedBlockKey(nb.Type, nb.Labels)] = struct{}{}
+	}
+	body.Blocks = nil
+	// nested syntax blocks and write blocks must keep the same order, see NewHclBlock.
+	baseWriteBlocks := wb.Body().Blocks()
+	for i, nb := range base.Body.Blocks {
+		if _, ok := overriddenTypes[nestedBlockKey(nb.Type, nb.Labels)]; ok {
+			wb.Body().RemoveBlock(baseWriteBlocks[i])
+			continue
+		}
+		body.Blocks = append(body.Blocks, nb)
+	}
+	overrideWriteBlocks := override.wb.Body().Blocks()
+	for i, nb := range override.Body.Blocks {
+		body.Blocks = append(body.Blocks, nb)
+		wb.Body().AppendBlock(overrideWriteBlocks[i])
+	}
+	rb := *base.Block
+	rb.Body = &body
+	return NewHclBlock(&rb, wb, base.ForEach)
+}
+
+// nestedBlockKey treats `dynamic "foo"` as `foo`, so override can replace dynamic blocks with static ones and vice versa.
+func nestedBlockKey(t string, labels []string) string {
+	if t == "dynamic" && len(labels) > 0 {
+		return labels[0]
+	}
+	return t
+}
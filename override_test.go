@@ -0,0 +1,129 @@
+package golden
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"github.com/zclconf/go-cty/cty"
+)
+
+type overrideSuite struct {
+	suite.Suite
+	*testBase
+}
+
+func TestOverrideSuite(t *testing.T) {
+	suite.Run(t, new(overrideSuite))
+}
+
+func (s *overrideSuite) SetupTest() {
+	s.testBase = newTestBase()
+}
+
+func (s *overrideSuite) TearDownTest() {
+	s.teardown()
+}
+
+func (s *overrideSuite) TestIsOverrideFile() {
+	s.True(isOverrideFile("/override.hcl"))
+	s.True(isOverrideFile("/dev_override.hcl"))
+	s.False(isOverrideFile("/main.hcl"))
+	s.False(isOverrideFile("/overrides.hcl"))
+}
+
+func (s *overrideSuite) TestOverride_AttributesAndNestedBlocksShouldBeMerged() {
+	s.dummyFsWithFiles(map[string]string{
+		"/main.hcl": `data "dummy" foo {
+  data = {
+    key = "value"
+  }
+  attribute = "base"
+  top_nested_block {
+    name = "base"
+  }
+}
+
+locals {
+  a = "base_a"
+  b = "base_b"
+}
+
+variable "v" {
+  default = "base"
+}
+`,
+		"/override.hcl": `data "dummy" foo {
+  data = {
+    key = "override"
+  }
+  top_nested_block {
+    name = "override"
+  }
+}
+
+locals {
+  b = "override_b"
+}
+`,
+		"/z_override.hcl": `variable "v" {
+  default = "z_override"
+}
+`,
+	})
+	config, err := BuildDummyConfig("/", "/", nil, nil)
+	require.NoError(s.T(), err)
+	_, err = RunDummyPlan(config)
+	require.NoError(s.T(), err)
+	foo := Blocks[*DummyData](config)[0]
+	s.Equal("override", foo.Tags["key"])
+	s.Equal("base", foo.AttributeWithDefaultValue)
+	require.Len(s.T(), foo.TopNestedBlocks, 1)
+	s.Equal("override", foo.TopNestedBlocks[0].Name)
+	values := config.EvalContext().Variables
+	s.Equal(cty.StringVal("base_a"), values["local"].GetAttr("a"))
+	s.Equal(cty.StringVal("override_b"), values["local"].GetAttr("b"))
+	s.Equal(cty.StringVal("z_override"), values["var"].GetAttr("v"))
+	for _, d := range Blocks[*DummyData](config) {
+		s.Equal("/override.hcl", d.HclBlock().Body.Attributes["data"].SrcRange.Filename)
+		s.Contains(d.HclBlock().Attributes()["data"].ExprString(), `"override"`)
+		s.Len(d.HclBlock().NestedBlocks(), 1)
+	}
+}
+
+func (s *overrideSuite) TestOverride_LaterOverrideFileShouldWin() {
+	s.dummyFsWithFiles(map[string]string{
+		"/main.hcl": `locals {
+  a = "base"
+}
+`,
+		"/a_override.hcl": `locals {
+  a = "a"
+}
+`,
+		"/b_override.hcl": `locals {
+  a = "b"
+}
+`,
+	})
+	config, err := BuildDummyConfig("/", "/", nil, nil)
+	require.NoError(s.T(), err)
+	s.Equal(cty.StringVal("b"), config.EvalContext().Variables["local"].GetAttr("a"))
+}
+
+func (s *overrideSuite) TestOverride_NonExistentTargetShouldReturnError() {
+	s.dummyFsWithFiles(map[string]string{
+		"/main.hcl": `locals {
+  a = "base"
+}
+`,
+		"/override.hcl": `data "dummy" missing {
+  data = {}
+}
+`,
+	})
+	_, err := BuildDummyConfig("/", "/", nil, nil)
+	require.NotNil(s.T(), err)
+	s.Contains(err.Error(), "override data.dummy.missing /override.hcl:1,")
+	s.Contains(err.Error(), "targets non-existent block")
+}
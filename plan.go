@@ -2,7 +2,11 @@ package golden
 
 import (
 	"fmt"
+	"time"
+
 	"github.com/hashicorp/go-multierror"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/zclconf/go-cty/cty"
 )
 
 type Plan interface {
@@ -11,18 +15,28 @@ type Plan interface {
 }
 
 func dagPlan(b Block) error {
+	notifyHooks(b.Config(), func(h Hook) { h.DecodeStarted(b) })
+	start := time.Now()
 	decodeErr := Decode(b)
+	recordDuration(b, MetricDecodeDuration, start)
+	notifyHooks(b.Config(), func(h Hook) { h.DecodeFinished(b, decodeErr) })
 	if decodeErr != nil {
 		return fmt.Errorf("%s(%s) Decode error: %+v", b.Address(), b.HclBlock().Range().String(), decodeErr)
 	}
+	if lifecycleErr := applyIgnoreChanges(b); lifecycleErr != nil {
+		return fmt.Errorf("%s(%s) lifecycle error: %+v", b.Address(), b.HclBlock().Range().String(), lifecycleErr)
+	}
 	if validateErr := Validate.Struct(b); validateErr != nil {
 		return fmt.Errorf("%s.%s.%s is not valid: %s", b.BlockType(), b.Type(), b.Name(), validateErr.Error())
 	}
+	start = time.Now()
 	failedChecks, preConditionCheckError := b.PreConditionCheck(b.EvalContext())
+	recordDuration(b, MetricExpressionDuration, start, Attr("golden.expression.kind", "precondition"))
 	if preConditionCheckError != nil {
 		return preConditionCheckError
 	}
 	if len(failedChecks) > 0 {
+		notifyHooks(b.Config(), func(h Hook) { h.PreconditionFailed(b, failedChecks) })
 		var err error
 		for _, c := range failedChecks {
 			err = multierror.Append(err, fmt.Errorf("precondition check error: %s, %s", c.ErrorMessage, c.Body.Range().String()))
@@ -31,11 +45,43 @@ func dagPlan(b Block) error {
 	}
 	pa, ok := b.(PlanBlock)
 	if ok {
-		execErr := pa.ExecuteDuringPlan()
+		execErr := withRetry(b, planPhase, pa.ExecuteDuringPlan)
 		if execErr != nil {
 			return fmt.Errorf("%s.%s.%s(%s) exec error: %+v", b.Type(), b.Type(), b.Name(), b.HclBlock().Range().String(), execErr)
 		}
 	}
+	// postconditions of apply blocks are checked after apply.
+	if _, ok := b.(ApplyBlock); !ok {
+		if err := postConditionCheck(b); err != nil {
+			return err
+		}
+	}
 	b.markReady()
 	return nil
 }
+
+func postConditionCheck(b Block) error {
+	ctx := b.EvalContext().NewChild()
+	ctx.Variables = map[string]cty.Value{
+		"self": blockToCtyValue(b),
+	}
+	start := time.Now()
+	failedChecks, err := b.PostConditionCheck(ctx)
+	recordDuration(b, MetricExpressionDuration, start, Attr("golden.expression.kind", "postcondition"))
+	if err != nil {
+		return err
+	}
+	var diags hcl.Diagnostics
+	for _, c := range failedChecks {
+		diags = diags.Append(&hcl.Diagnostic{
+			Severity: hcl.DiagError,
+			Summary:  "postcondition check error",
+			Detail:   fmt.Sprintf("%s: %s", b.Address(), c.ErrorMessage),
+			Subject:  c.conditionRange(),
+		})
+	}
+	if diags.HasErrors() {
+		return diags
+	}
+	return nil
+}
@@ -0,0 +1,253 @@
+package golden
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/zclconf/go-cty/cty"
+	ctyjson "github.com/zclconf/go-cty/cty/json"
+)
+
+type DiffAction string
+
+const (
+	DiffActionAdded   DiffAction = "added"
+	DiffActionRemoved DiffAction = "removed"
+	DiffActionChanged DiffAction = "changed"
+)
+
+var diffActionSymbols = map[DiffAction]string{
+	DiffActionAdded:   "+",
+	DiffActionRemoved: "-",
+	DiffActionChanged: "~",
+}
+
+var diffActionColors = map[DiffAction]string{
+	DiffActionAdded:   "\033[32m",
+	DiffActionRemoved: "\033[31m",
+	DiffActionChanged: "\033[33m",
+}
+
+const colorReset = "\033[0m"
+
+// PlanDiff lists blocks that differ between two plans, blocks are matched by address and unchanged blocks are omitted.
+type PlanDiff struct {
+	Blocks []BlockDiff `json:"blocks"`
+}
+
+type BlockDiff struct {
+	Address string        `json:"address"`
+	Action  DiffAction    `json:"action"`
+	Changes []ValueChange `json:"changes"`
+}
+
+// ValueChange is a change of a single value, Path is like `tags.env` or `nested_block[0].name`.
+// Before is cty.NilVal for added values, After is cty.NilVal for removed values.
+// Before and After are unmarked, Sensitive is true if either of them was marked, like values returned by `sensitive()`, they're redacted in Render and JSON.
+type ValueChange struct {
+	Path      string     `json:"path"`
+	Action    DiffAction `json:"action"`
+	Before    cty.Value  `json:"-"`
+	After     cty.Value  `json:"-"`
+	Sensitive bool       `json:"sensitive,omitempty"`
+}
+
+func (c ValueChange) MarshalJSON() ([]byte, error) {
+	type valueChange ValueChange
+	return json.Marshal(struct {
+		valueChange
+		Before *ctyjson.SimpleJSONValue `json:"before,omitempty"`
+		After  *ctyjson.SimpleJSONValue `json:"after,omitempty"`
+	}{
+		valueChange: valueChange(c),
+		Before:      diffJsonValue(c.Before, c.Sensitive),
+		After:       diffJsonValue(c.After, c.Sensitive),
+	})
+}
+
+func diffJsonValue(v cty.Value, sensitive bool) *ctyjson.SimpleJSONValue {
+	if v == cty.NilVal || sensitive {
+		return nil
+	}
+	return &ctyjson.SimpleJSONValue{Value: cty.UnknownAsNull(v)}
+}
+
+// DiffPlans compares values of blocks in two plan results, e.g. plans of the main branch and a pull request.
+func DiffPlans(before, after *PlanResult) *PlanDiff {
+	beforeBlocks := make(map[string]PlannedBlock)
+	afterBlocks := make(map[string]PlannedBlock)
+	addresses := make(map[string]struct{})
+	for _, b := range before.Blocks {
+		beforeBlocks[b.Address] = b
+		addresses[b.Address] = struct{}{}
+	}
+	for _, b := range after.Blocks {
+		afterBlocks[b.Address] = b
+		addresses[b.Address] = struct{}{}
+	}
+	d := &PlanDiff{}
+	for _, address := range sortedKeys(addresses) {
+		b, inBefore := beforeBlocks[address]
+		a, inAfter := afterBlocks[address]
+		bd := BlockDiff{
+			Address: address,
+			Action:  DiffActionChanged,
+			Changes: diffValueMaps("", b.Values, a.Values),
+		}
+		switch {
+		case !inBefore:
+			bd.Action = DiffActionAdded
+		case !inAfter:
+			bd.Action = DiffActionRemoved
+		case len(bd.Changes) == 0:
+			continue
+		}
+		d.Blocks = append(d.Blocks, bd)
+	}
+	return d
+}
+
+func diffValueMaps(path string, before, after map[string]cty.Value) []ValueChange {
+	names := make(map[string]struct{})
+	for n := range before {
+		names[n] = struct{}{}
+	}
+	for n := range after {
+		names[n] = struct{}{}
+	}
+	var changes []ValueChange
+	for _, n := range sortedKeys(names) {
+		changes = append(changes, diffValues(attributePath(path, n), before[n], after[n])...)
+	}
+	return changes
+}
+
+func diffValues(path string, before, after cty.Value) []ValueChange {
+	beforeAbsent := before == cty.NilVal || before.IsNull()
+	afterAbsent := after == cty.NilVal || after.IsNull()
+	// values marked as a whole are compared as a single value, unmarked containers are walked so changes of other elements are reported as usual.
+	sensitive := !beforeAbsent && before.IsMarked() || !afterAbsent && after.IsMarked()
+	leaf := func(action DiffAction) []ValueChange {
+		c := ValueChange{Path: path, Action: action, Before: cty.NilVal, After: cty.NilVal}
+		if !beforeAbsent && action != DiffActionAdded {
+			c.Sensitive = c.Sensitive || before.ContainsMarked()
+			c.Before, _ = before.UnmarkDeep()
+		}
+		if !afterAbsent && action != DiffActionRemoved {
+			c.Sensitive = c.Sensitive || after.ContainsMarked()
+			c.After, _ = after.UnmarkDeep()
+		}
+		return []ValueChange{c}
+	}
+	switch {
+	case beforeAbsent && afterAbsent:
+		return nil
+	case beforeAbsent:
+		return leaf(DiffActionAdded)
+	case afterAbsent:
+		return leaf(DiffActionRemoved)
+	}
+	unmarkedBefore, _ := before.UnmarkDeep()
+	unmarkedAfter, _ := after.UnmarkDeep()
+	if unmarkedBefore.RawEquals(unmarkedAfter) {
+		return nil
+	}
+	if sensitive || !before.IsKnown() || !after.IsKnown() {
+		return leaf(DiffActionChanged)
+	}
+	bt, at := before.Type(), after.Type()
+	if isMapping(bt) && isMapping(at) {
+		return diffValueMaps(path, before.AsValueMap(), after.AsValueMap())
+	}
+	if isSequence(bt) && isSequence(at) {
+		bs, as := before.AsValueSlice(), after.AsValueSlice()
+		var changes []ValueChange
+		for i := 0; i < max(len(bs), len(as)); i++ {
+			var b, a = cty.NilVal, cty.NilVal
+			if i < len(bs) {
+				b = bs[i]
+			}
+			if i < len(as) {
+				a = as[i]
+			}
+			changes = append(changes, diffValues(fmt.Sprintf("%s[%d]", path, i), b, a)...)
+		}
+		return changes
+	}
+	return leaf(DiffActionChanged)
+}
+
+func isMapping(t cty.Type) bool {
+	return t.IsObjectType() || t.IsMapType()
+}
+
+func isSequence(t cty.Type) bool {
+	return t.IsListType() || t.IsTupleType()
+}
+
+func attributePath(path, name string) string {
+	if !hclsyntax.ValidIdentifier(name) {
+		return fmt.Sprintf("%s[%q]", path, name)
+	}
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
+
+// String renders the diff without colors.
+func (d *PlanDiff) String() string {
+	return d.Render(false)
+}
+
+// Render renders a human-readable change summary, changes are colored with ANSI escape codes if color is true.
+func (d *PlanDiff) Render(color bool) string {
+	sb := strings.Builder{}
+	counts := make(map[DiffAction]int)
+	for _, b := range d.Blocks {
+		counts[b.Action]++
+		sb.WriteString(colored(color, b.Action, fmt.Sprintf("%s %s", diffActionSymbols[b.Action], b.Address)))
+		sb.WriteString("\n")
+		for _, c := range b.Changes {
+			before, after := logValue(c.Before, c.Sensitive), logValue(c.After, c.Sensitive)
+			var line string
+			switch c.Action {
+			case DiffActionAdded:
+				line = fmt.Sprintf("%s %s: %s", diffActionSymbols[c.Action], c.Path, after)
+			case DiffActionRemoved:
+				line = fmt.Sprintf("%s %s: %s", diffActionSymbols[c.Action], c.Path, before)
+			default:
+				line = fmt.Sprintf("%s %s: %s -> %s", diffActionSymbols[c.Action], c.Path, before, after)
+			}
+			sb.WriteString("    " + colored(color, c.Action, line) + "\n")
+		}
+	}
+	fmt.Fprintf(&sb, "Plan diff: %d added, %d changed, %d removed.", counts[DiffActionAdded], counts[DiffActionChanged], counts[DiffActionRemoved])
+	return sb.String()
+}
+
+// JSON returns the diff as JSON, values are written as plain JSON values, before and after of sensitive changes are omitted.
+func (d *PlanDiff) JSON() ([]byte, error) {
+	return json.MarshalIndent(d, "", "  ")
+}
+
+func colored(color bool, action DiffAction, s string) string {
+	if !color {
+		return s
+	}
+	return diffActionColors[action] + s + colorReset
+}
+
+// renderValue renders v in HCL syntax, marked values are never rendered.
+func renderValue(v cty.Value) string {
+	if v.ContainsMarked() {
+		return redactedValue
+	}
+	if !v.IsWhollyKnown() {
+		return "(known after apply)"
+	}
+	return string(hclwrite.TokensForValue(v).Bytes())
+}
@@ -0,0 +1,184 @@
+package golden
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/lonegunmanb/hclfuncs/marks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"github.com/zclconf/go-cty/cty"
+)
+
+type planDiffSuite struct {
+	suite.Suite
+	*testBase
+}
+
+func TestPlanDiffSuite(t *testing.T) {
+	suite.Run(t, new(planDiffSuite))
+}
+
+func (s *planDiffSuite) SetupTest() {
+	s.testBase = newTestBase()
+}
+
+func (s *planDiffSuite) TearDownTest() {
+	s.teardown()
+}
+
+func (s *planDiffSuite) planResult(dir, config string) *PlanResult {
+	s.dummyFsWithFiles(map[string]string{
+		dir + "/main.hcl": config,
+	})
+	c, err := BuildDummyConfig(dir, dir, nil, nil)
+	require.NoError(s.T(), err)
+	_, err = RunDummyPlan(c)
+	require.NoError(s.T(), err)
+	return c.(*DummyConfig).PlanResult()
+}
+
+func (s *planDiffSuite) TestDiffPlans() {
+	before := s.planResult("/main", `resource "dummy" "foo" {
+  tags = {
+    env   = "dev"
+    owner = "alice"
+  }
+  nested_block {
+    id   = 1
+    name = "a"
+  }
+}
+
+resource "dummy" "removed" {
+}
+
+locals {
+  same = 1
+}
+`)
+	after := s.planResult("/pr", `resource "dummy" "foo" {
+  tags = {
+    env        = "prod"
+    "cost center" = "42"
+  }
+  nested_block {
+    id   = 1
+    name = "b"
+  }
+  nested_block {
+    id   = 2
+    name = "c"
+  }
+}
+
+resource "dummy" "added" {
+  tags = {
+    a = "b"
+  }
+}
+
+locals {
+  same = 1
+}
+`)
+	diff := DiffPlans(before, after)
+	var addresses []string
+	for _, b := range diff.Blocks {
+		addresses = append(addresses, b.Address)
+	}
+	s.Equal([]string{"resource.dummy.added", "resource.dummy.foo", "resource.dummy.removed"}, addresses)
+	s.Equal(DiffActionAdded, diff.Blocks[0].Action)
+	s.Equal(DiffActionRemoved, diff.Blocks[2].Action)
+	foo := diff.Blocks[1]
+	s.Equal(DiffActionChanged, foo.Action)
+	s.Equal([]ValueChange{
+		{Path: "nested_block[0].name", Action: DiffActionChanged, Before: cty.StringVal("a"), After: cty.StringVal("b")},
+		{Path: "nested_block[1]", Action: DiffActionAdded, Before: cty.NilVal, After: foo.Changes[1].After},
+		{Path: `tags["cost center"]`, Action: DiffActionAdded, Before: cty.NilVal, After: cty.StringVal("42")},
+		{Path: "tags.env", Action: DiffActionChanged, Before: cty.StringVal("dev"), After: cty.StringVal("prod")},
+		{Path: "tags.owner", Action: DiffActionRemoved, Before: cty.StringVal("alice"), After: cty.NilVal},
+	}, foo.Changes)
+
+	text := diff.String()
+	s.Contains(text, "~ resource.dummy.foo\n")
+	s.Contains(text, `    ~ tags.env: "dev" -> "prod"`)
+	s.Contains(text, `    - tags.owner: "alice"`)
+	s.Contains(text, "+ resource.dummy.added\n")
+	s.Contains(text, "Plan diff: 1 added, 1 changed, 1 removed.")
+	s.NotContains(text, "\033[")
+	s.Contains(diff.Render(true), "\033[33m~ resource.dummy.foo\033[0m")
+
+	j, err := diff.JSON()
+	require.NoError(s.T(), err)
+	var decoded map[string]any
+	require.NoError(s.T(), json.Unmarshal(j, &decoded))
+	change := decoded["blocks"].([]any)[1].(map[string]any)["changes"].([]any)[3].(map[string]any)
+	s.Equal(map[string]any{"path": "tags.env", "action": "changed", "before": "dev", "after": "prod"}, change)
+	removed := decoded["blocks"].([]any)[1].(map[string]any)["changes"].([]any)[4].(map[string]any)
+	s.NotContains(removed, "after")
+}
+
+func TestDiffValues_UnknownAndTypeChange(t *testing.T) {
+	changes := diffValues("v", cty.StringVal("a"), cty.UnknownVal(cty.String))
+	require.Len(t, changes, 1)
+	assert.Equal(t, DiffActionChanged, changes[0].Action)
+	assert.Equal(t, "(known after apply)", renderValue(changes[0].After))
+
+	changes = diffValues("v", cty.StringVal("1"), cty.ListVal([]cty.Value{cty.StringVal("1")}))
+	require.Len(t, changes, 1)
+	assert.Equal(t, "v", changes[0].Path)
+	assert.Empty(t, diffValues("v", cty.NullVal(cty.String), cty.NilVal))
+}
+
+func (s *planDiffSuite) TestDiffPlans_SensitiveValues() {
+	before := s.planResult("/main", `locals {
+  password = sensitive("old")
+  tags = {
+    env   = "dev"
+    token = sensitive("t1")
+  }
+}
+`)
+	after := s.planResult("/pr", `locals {
+  password = sensitive("new")
+  tags = {
+    env   = "prod"
+    token = sensitive("t2")
+  }
+}
+`)
+	diff := DiffPlans(before, after)
+	var changes []ValueChange
+	for _, b := range diff.Blocks {
+		changes = append(changes, b.Changes...)
+	}
+	s.Contains(changes, ValueChange{Path: "value", Action: DiffActionChanged, Before: cty.StringVal("old"), After: cty.StringVal("new"), Sensitive: true})
+	s.Contains(changes, ValueChange{Path: "value.env", Action: DiffActionChanged, Before: cty.StringVal("dev"), After: cty.StringVal("prod")})
+	s.Contains(changes, ValueChange{Path: "value.token", Action: DiffActionChanged, Before: cty.StringVal("t1"), After: cty.StringVal("t2"), Sensitive: true})
+
+	text := diff.String()
+	s.Contains(text, `~ value.env: "dev" -> "prod"`)
+	s.Contains(text, "~ value.token: (sensitive value) -> (sensitive value)")
+	for _, secret := range []string{"old", "new", "t1", "t2"} {
+		s.NotContains(text, secret)
+	}
+
+	j, err := diff.JSON()
+	require.NoError(s.T(), err)
+	for _, secret := range []string{"old", "new", "t1", "t2"} {
+		s.NotContains(string(j), secret)
+	}
+	s.Contains(string(j), `"sensitive": true`)
+}
+
+func TestDiffValues_SensitiveUnchanged(t *testing.T) {
+	v := cty.StringVal("a").Mark(marks.Sensitive)
+	assert.Empty(t, diffValues("v", v, v))
+	assert.Empty(t, diffValues("v", cty.ObjectVal(map[string]cty.Value{"a": v}), cty.ObjectVal(map[string]cty.Value{"a": v})))
+	changes := diffValues("v", cty.NilVal, v)
+	require.Len(t, changes, 1)
+	assert.True(t, changes[0].Sensitive)
+	assert.Equal(t, redactedValue, renderValue(v))
+}
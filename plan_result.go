@@ -0,0 +1,221 @@
+package golden
+
+import (
+	"encoding/json"
+	"errors"
+	"sort"
+
+	"github.com/hashicorp/go-multierror"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/zclconf/go-cty/cty"
+	ctyjson "github.com/zclconf/go-cty/cty/json"
+)
+
+// PlanResultFormatVersion is the version of PlanResult JSON format, it changes when the format changes incompatibly.
+const PlanResultFormatVersion = "1.0"
+
+type PlanStatus string
+
+const (
+	// PlanStatusPlanned means the block has been decoded and executed during plan.
+	PlanStatusPlanned PlanStatus = "planned"
+	// PlanStatusFailed means the block returned an error during plan.
+	PlanStatusFailed PlanStatus = "failed"
+	// PlanStatusSkipped means the block has not been planned, usually because an upstream block failed.
+	PlanStatusSkipped PlanStatus = "skipped"
+)
+
+// PlanResult lists every block of a config after RunPlan, it could be serialised to JSON like:
+//
+//	{
+//	  "format_version": "1.0",
+//	  "workspace": "default",
+//	  "blocks": [
+//	    {
+//	      "address": "data.dummy.foo",
+//	      "block_type": "data",
+//	      "type": "dummy",
+//	      "name": "foo",
+//	      "range": {"filename": "main.hcl", "start": {"line": 1, "column": 1, "byte": 0}, "end": {...}},
+//	      "for_each_key": "a",
+//	      "values": {"data": {"key": "value"}},
+//	      "status": "planned",
+//	      "diagnostics": [{"severity": "error", "summary": "...", "detail": "...", "range": {...}}]
+//	    }
+//	  ]
+//	}
+//
+// `for_each_key`, `range` and `diagnostics` are omitted when they're empty, unknown values and sensitive values, like values returned by `sensitive()`, are written as null.
+type PlanResult struct {
+	FormatVersion string         `json:"format_version"`
+	Workspace     string         `json:"workspace"`
+	Blocks        []PlannedBlock `json:"blocks"`
+}
+
+type PlannedBlock struct {
+	Address    string               `json:"address"`
+	BlockType  string               `json:"block_type"`
+	Type       string               `json:"type,omitempty"`
+	Name       string               `json:"name"`
+	Range      *SourceRange         `json:"range,omitempty"`
+	ForEachKey *string              `json:"for_each_key,omitempty"`
+	Values     map[string]cty.Value `json:"-"`
+	Status     PlanStatus           `json:"status"`
+	// Attempts is how many times the block has been executed during plan, it's more than 1 when retried.
+	Attempts    int              `json:"attempts,omitempty"`
+	Diagnostics []PlanDiagnostic `json:"diagnostics,omitempty"`
+}
+
+type PlanDiagnostic struct {
+	Severity string       `json:"severity"`
+	Summary  string       `json:"summary"`
+	Detail   string       `json:"detail,omitempty"`
+	Range    *SourceRange `json:"range,omitempty"`
+}
+
+// SourceRange is hcl.Range with JSON field names.
+type SourceRange struct {
+	Filename string    `json:"filename"`
+	Start    SourcePos `json:"start"`
+	End      SourcePos `json:"end"`
+}
+
+type SourcePos struct {
+	Line   int `json:"line"`
+	Column int `json:"column"`
+	Byte   int `json:"byte"`
+}
+
+func newSourceRange(r *hcl.Range) *SourceRange {
+	if r == nil || r.Filename == "" {
+		return nil
+	}
+	return &SourceRange{
+		Filename: r.Filename,
+		Start:    SourcePos{Line: r.Start.Line, Column: r.Start.Column, Byte: r.Start.Byte},
+		End:      SourcePos{Line: r.End.Line, Column: r.End.Column, Byte: r.End.Byte},
+	}
+}
+
+func (b PlannedBlock) MarshalJSON() ([]byte, error) {
+	type plannedBlock PlannedBlock
+	values := make(map[string]ctyjson.SimpleJSONValue)
+	for n, v := range b.Values {
+		v, _ = redactSensitive(v)
+		values[n] = ctyjson.SimpleJSONValue{Value: cty.UnknownAsNull(v)}
+	}
+	return json.Marshal(struct {
+		plannedBlock
+		Values map[string]ctyjson.SimpleJSONValue `json:"values"`
+	}{
+		plannedBlock: plannedBlock(b),
+		Values:       values,
+	})
+}
+
+// JSON returns PlanResult in the documented JSON format.
+func (r *PlanResult) JSON() ([]byte, error) {
+	return json.MarshalIndent(r, "", "  ")
+}
+
+// HasErrors returns true if any block failed during plan.
+func (r *PlanResult) HasErrors() bool {
+	for _, b := range r.Blocks {
+		if b.Status == PlanStatusFailed {
+			return true
+		}
+	}
+	return false
+}
+
+// PlanResult returns results of all blocks ordered by address, it should be called after RunPlan.
+func (c *BaseConfig) PlanResult() *PlanResult {
+	r := &PlanResult{
+		FormatVersion: PlanResultFormatVersion,
+		Workspace:     c.Workspace(),
+	}
+	bs := blocks(c)
+	sort.Slice(bs, func(i, j int) bool {
+		return bs[i].Address() < bs[j].Address()
+	})
+	for _, b := range bs {
+		r.Blocks = append(r.Blocks, c.plannedBlock(b))
+	}
+	return r
+}
+
+func (c *BaseConfig) plannedBlock(b Block) PlannedBlock {
+	pb := PlannedBlock{
+		Address:   b.Address(),
+		BlockType: b.BlockType(),
+		Type:      b.Type(),
+		Name:      b.Name(),
+		Values:    Value(b),
+		Status:    PlanStatusSkipped,
+	}
+	r := b.HclBlock().Range()
+	pb.Range = newSourceRange(&r)
+	if fe := b.getForEach(); fe != nil {
+		key := CtyValueToString(fe.key)
+		pb.ForEachKey = &key
+	}
+	if a, ok := b.(interface{ PlanAttempts() int }); ok {
+		pb.Attempts = a.PlanAttempts()
+	}
+	if sv, ok := b.(SingleValueBlock); ok && len(pb.Values) == 0 {
+		pb.Values = map[string]cty.Value{
+			"value": sv.Value(),
+		}
+	}
+	err, planned := c.planErrors[b.Address()]
+	switch {
+	case !planned:
+	case err != nil:
+		pb.Status = PlanStatusFailed
+		pb.Diagnostics = planDiagnostics(err)
+	default:
+		pb.Status = PlanStatusPlanned
+	}
+	if cb, ok := b.(*CheckBlock); ok {
+		pb.Diagnostics = append(pb.Diagnostics, toPlanDiagnostics(cb.Result().Diagnostics)...)
+	}
+	return pb
+}
+
+func planDiagnostics(err error) []PlanDiagnostic {
+	var diags hcl.Diagnostics
+	if errors.As(err, &diags) {
+		return toPlanDiagnostics(diags)
+	}
+	var me *multierror.Error
+	if errors.As(err, &me) && len(me.Errors) > 1 {
+		var r []PlanDiagnostic
+		for _, e := range me.Errors {
+			r = append(r, planDiagnostics(e)...)
+		}
+		return r
+	}
+	return []PlanDiagnostic{
+		{
+			Severity: "error",
+			Summary:  err.Error(),
+		},
+	}
+}
+
+func toPlanDiagnostics(diags hcl.Diagnostics) []PlanDiagnostic {
+	var r []PlanDiagnostic
+	for _, d := range diags {
+		severity := "error"
+		if d.Severity == hcl.DiagWarning {
+			severity = "warning"
+		}
+		r = append(r, PlanDiagnostic{
+			Severity: severity,
+			Summary:  d.Summary,
+			Detail:   d.Detail,
+			Range:    newSourceRange(d.Subject),
+		})
+	}
+	return r
+}
@@ -0,0 +1,153 @@
+package golden
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"github.com/zclconf/go-cty/cty"
+)
+
+type planResultSuite struct {
+	suite.Suite
+	*testBase
+}
+
+func TestPlanResultSuite(t *testing.T) {
+	suite.Run(t, new(planResultSuite))
+}
+
+func (s *planResultSuite) SetupTest() {
+	s.testBase = newTestBase()
+}
+
+func (s *planResultSuite) TearDownTest() {
+	s.teardown()
+}
+
+func (s *planResultSuite) TestPlanResult_BlocksAndJson() {
+	s.dummyFsWithFiles(map[string]string{
+		"/main.hcl": `locals {
+  keys = ["a", "b"]
+}
+
+data "dummy" "foo" {
+  for_each = toset(local.keys)
+  data = {
+    key = each.value
+  }
+}
+
+output "o" {
+  value = data.dummy.foo["a"].data.key
+}
+`,
+	})
+	config, err := BuildDummyConfig("/", "/", nil, nil)
+	require.NoError(s.T(), err)
+	_, err = RunDummyPlan(config)
+	require.NoError(s.T(), err)
+	result := config.(*DummyConfig).PlanResult()
+	s.False(result.HasErrors())
+	var addresses []string
+	for _, b := range result.Blocks {
+		addresses = append(addresses, b.Address)
+		s.Equal(PlanStatusPlanned, b.Status, b.Address)
+	}
+	s.Equal([]string{"data.dummy.foo[a]", "data.dummy.foo[b]", "local.keys", "output.o"}, addresses)
+	foo := result.Blocks[0]
+	s.Equal("data", foo.BlockType)
+	s.Equal("dummy", foo.Type)
+	s.Equal("foo", foo.Name)
+	s.Equal("a", *foo.ForEachKey)
+	s.Equal("/main.hcl", foo.Range.Filename)
+	s.Equal(5, foo.Range.Start.Line)
+	s.Equal(cty.MapVal(map[string]cty.Value{"key": cty.StringVal("a")}), foo.Values["data"])
+	s.Equal(cty.StringVal("a"), result.Blocks[3].Values["value"])
+
+	j, err := result.JSON()
+	require.NoError(s.T(), err)
+	var decoded map[string]any
+	require.NoError(s.T(), json.Unmarshal(j, &decoded))
+	s.Equal(PlanResultFormatVersion, decoded["format_version"])
+	s.Equal("default", decoded["workspace"])
+	block := decoded["blocks"].([]any)[0].(map[string]any)
+	s.Equal("data.dummy.foo[a]", block["address"])
+	s.Equal("a", block["for_each_key"])
+	s.Equal("planned", block["status"])
+	s.Equal(map[string]any{"key": "a"}, block["values"].(map[string]any)["data"])
+	s.Equal(float64(5), block["range"].(map[string]any)["start"].(map[string]any)["line"])
+	s.NotContains(block, "diagnostics")
+}
+
+func (s *planResultSuite) TestPlanResult_FailedAndSkippedBlocks() {
+	s.dummyFsWithFiles(map[string]string{
+		"/main.hcl": `data "dummy" "foo" {
+  precondition {
+    condition     = false
+    error_message = "foo is not ready"
+  }
+}
+
+data "dummy" "bar" {
+  data = data.dummy.foo.data
+}
+
+check "c" {
+  assert {
+    condition     = false
+    error_message = "check failed"
+  }
+}
+`,
+	})
+	config, err := BuildDummyConfig("/", "/", nil, nil)
+	require.NoError(s.T(), err)
+	_, err = RunDummyPlan(config)
+	require.NotNil(s.T(), err)
+	result := config.(*DummyConfig).PlanResult()
+	s.True(result.HasErrors())
+	statuses := make(map[string]PlannedBlock)
+	for _, b := range result.Blocks {
+		statuses[b.Address] = b
+	}
+	s.Equal(PlanStatusSkipped, statuses["data.dummy.bar"].Status)
+	foo := statuses["data.dummy.foo"]
+	s.Equal(PlanStatusFailed, foo.Status)
+	require.Len(s.T(), foo.Diagnostics, 1)
+	s.Equal("error", foo.Diagnostics[0].Severity)
+	s.Contains(foo.Diagnostics[0].Summary, "foo is not ready")
+	c := statuses["check.c"]
+	s.Equal(PlanStatusPlanned, c.Status)
+	require.Len(s.T(), c.Diagnostics, 1)
+	s.Equal("warning", c.Diagnostics[0].Severity)
+	s.NotNil(c.Diagnostics[0].Range)
+	_, err = result.JSON()
+	s.NoError(err)
+}
+
+func (s *planResultSuite) TestPlanResult_SensitiveValuesAreRedactedInJson() {
+	s.dummyFsWithFiles(map[string]string{
+		"/main.hcl": `locals {
+  password = sensitive("p@ssw0rd")
+  object = {
+    name   = "a"
+    secret = sensitive("s")
+  }
+}
+`,
+	})
+	config, err := BuildDummyConfig("/", "/", nil, nil)
+	require.NoError(s.T(), err)
+	_, err = RunDummyPlan(config)
+	require.NoError(s.T(), err)
+	content, err := config.(*DummyConfig).PlanResult().JSON()
+	require.NoError(s.T(), err)
+	s.NotContains(string(content), "p@ssw0rd")
+	var decoded map[string]any
+	require.NoError(s.T(), json.Unmarshal(content, &decoded))
+	blocks := decoded["blocks"].([]any)
+	s.Equal(map[string]any{"name": "a", "secret": nil}, blocks[0].(map[string]any)["values"].(map[string]any)["value"])
+	s.Nil(blocks[1].(map[string]any)["values"].(map[string]any)["value"])
+}
@@ -0,0 +1,19 @@
+package golden
+
+import (
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+)
+
+type PostCondition struct {
+	Body         *hclsyntax.Body
+	Condition    bool   `hcl:"condition"`
+	ErrorMessage string `hcl:"error_message,optional"`
+}
+
+func (c PostCondition) conditionRange() *hcl.Range {
+	if attr, ok := c.Body.Attributes["condition"]; ok {
+		return attr.SrcRange.Ptr()
+	}
+	return c.Body.Range().Ptr()
+}
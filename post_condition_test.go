@@ -0,0 +1,138 @@
+package golden
+
+import (
+	"testing"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type postConditionSuite struct {
+	suite.Suite
+	*testBase
+}
+
+func TestPostConditionSuite(t *testing.T) {
+	suite.Run(t, new(postConditionSuite))
+}
+
+func (s *postConditionSuite) SetupTest() {
+	s.testBase = newTestBase()
+}
+
+func (s *postConditionSuite) TearDownTest() {
+	s.teardown()
+}
+
+func (s *postConditionSuite) TestPostCondition_PassedSelfReference() {
+	content := `
+    data "dummy" foo {
+        data = {
+            key = "value"
+        }
+        postcondition {
+            condition = self.data["key"] == "value" && self.attribute == "default_value"
+            error_message = "unexpected data"
+        }
+    }
+    `
+	s.dummyFsWithFiles(map[string]string{
+		"test.hcl": content,
+	})
+	config, err := BuildDummyConfig("", "", nil, nil)
+	require.NoError(s.T(), err)
+	_, err = RunDummyPlan(config)
+	s.NoError(err)
+}
+
+func (s *postConditionSuite) TestPostCondition_FailedCheckShouldFailPlanWithRange() {
+	content := `data "dummy" foo {
+  data = {
+    key = "value"
+  }
+  postcondition {
+    condition = self.data["key"] != "value"
+    error_message = "key must not be value"
+  }
+}
+`
+	s.dummyFsWithFiles(map[string]string{
+		"test.hcl": content,
+	})
+	config, err := BuildDummyConfig("", "", nil, nil)
+	require.NoError(s.T(), err)
+	_, err = RunDummyPlan(config)
+	require.NotNil(s.T(), err)
+	s.Contains(err.Error(), "data.dummy.foo: key must not be value")
+	s.Contains(err.Error(), "test.hcl:6,5-44")
+	var diags hcl.Diagnostics
+	s.ErrorAs(err, &diags)
+}
+
+func (s *postConditionSuite) TestPostCondition_ForEachBlockShouldUseInstanceValue() {
+	content := `
+    data "dummy" foo {
+        for_each = toset(["a", "b"])
+        data = {
+            key = each.value
+        }
+        postcondition {
+            condition = self.data["key"] == each.key
+            error_message = "key must equal to each.key"
+        }
+    }
+    `
+	s.dummyFsWithFiles(map[string]string{
+		"test.hcl": content,
+	})
+	config, err := BuildDummyConfig("", "", nil, nil)
+	require.NoError(s.T(), err)
+	_, err = RunDummyPlan(config)
+	s.NoError(err)
+}
+
+func (s *postConditionSuite) TestPostCondition_ApplyBlockShouldBeCheckedAfterApply() {
+	content := `
+    resource "dummy" foo {
+        tags = {
+            env = "dev"
+        }
+        postcondition {
+            condition = self.tags["env"] == "prod"
+            error_message = "env must be prod"
+        }
+    }
+    `
+	s.dummyFsWithFiles(map[string]string{
+		"test.hcl": content,
+	})
+	config, err := BuildDummyConfig("", "", nil, nil)
+	require.NoError(s.T(), err)
+	_, err = RunDummyPlan(config)
+	s.NoError(err)
+	err = config.RunApply()
+	require.NotNil(s.T(), err)
+	s.Contains(err.Error(), "env must be prod")
+}
+
+func (s *postConditionSuite) TestPostCondition_ShouldNotBeDecodedAsAttribute() {
+	content := `
+    data "dummy" foo {
+        postcondition {
+            condition = true
+        }
+    }
+    `
+	s.dummyFsWithFiles(map[string]string{
+		"test.hcl": content,
+	})
+	config, err := BuildDummyConfig("", "", nil, nil)
+	require.NoError(s.T(), err)
+	_, err = RunDummyPlan(config)
+	s.NoError(err)
+	d := Blocks[TestData](config)[0].(*DummyData)
+	check, err := d.PostConditionCheck(new(hcl.EvalContext))
+	s.NoError(err)
+	s.Len(check, 0)
+}
@@ -0,0 +1,169 @@
+package golden
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/zclconf/go-cty/cty"
+	"github.com/zclconf/go-cty/cty/gocty"
+)
+
+const retryBlockType = "retry"
+
+const (
+	planPhase  = "plan"
+	applyPhase = "apply"
+)
+
+const (
+	defaultRetryBackoff    = time.Second
+	defaultRetryMaxBackoff = 30 * time.Second
+)
+
+// RetryPolicy retries failed ExecuteDuringPlan and Apply, the delay doubles after each attempt from Backoff up to MaxBackoff, it's not capped if MaxBackoff is 0.
+// It could be set for all blocks by NewBaseConfigArgs.RetryPolicy, or per block by a `retry` meta block:
+//
+//	retry {
+//	  attempts    = 3
+//	  backoff     = "2s"
+//	  max_backoff = "30s"
+//	}
+type RetryPolicy struct {
+	// Attempts is the max number of executions including the first one.
+	Attempts   int
+	Backoff    time.Duration
+	MaxBackoff time.Duration
+}
+
+// delay returns how long to wait after the attempt-th failure.
+func (p *RetryPolicy) delay(attempt int) time.Duration {
+	d := p.Backoff
+	// stop doubling before d overflows when there's no cap.
+	for i := 1; i < attempt && d < math.MaxInt64/2 && (p.MaxBackoff <= 0 || d < p.MaxBackoff); i++ {
+		d *= 2
+	}
+	if p.MaxBackoff > 0 && d > p.MaxBackoff {
+		d = p.MaxBackoff
+	}
+	return d
+}
+
+var retrySleep = func(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
+
+// withRetry executes f with the retry policy of b and records the number of attempts for phase.
+func withRetry(b Block, phase string, f func() error) error {
+	policy, err := retryPolicy(b)
+	if err != nil {
+		return err
+	}
+	ctx := context.Background()
+	if b.Config() != nil {
+		ctx = b.Config().Context()
+	}
+	attempts := 1
+	if policy != nil && policy.Attempts > 1 {
+		attempts = policy.Attempts
+	}
+	notifyHooks(b.Config(), func(h Hook) { h.ExecuteStarted(b, phase) })
+	start := time.Now()
+	execErr := retry(ctx, b, phase, policy, attempts, f)
+	notifyHooks(b.Config(), func(h Hook) { h.ExecuteFinished(b, phase, time.Since(start), execErr) })
+	return execErr
+}
+
+func retry(ctx context.Context, b Block, phase string, policy *RetryPolicy, attempts int, f func() error) error {
+	var execErr error
+	for attempt := 1; attempt <= attempts; attempt++ {
+		recordAttempts(b, phase, attempt)
+		if execErr = f(); execErr == nil {
+			return nil
+		}
+		if attempt == attempts {
+			break
+		}
+		if sleepErr := retrySleep(ctx, policy.delay(attempt)); sleepErr != nil {
+			return fmt.Errorf("retry cancelled after %d attempts: %w, last error: %+v", attempt, sleepErr, execErr)
+		}
+	}
+	if attempts > 1 {
+		return fmt.Errorf("failed after %d attempts: %w", attempts, execErr)
+	}
+	return execErr
+}
+
+func recordAttempts(b Block, phase string, attempts int) {
+	if r, ok := b.(interface{ setAttempts(string, int) }); ok {
+		r.setAttempts(phase, attempts)
+	}
+}
+
+// retryPolicy returns the policy from the `retry` meta block, or the config's default policy.
+func retryPolicy(b Block) (*RetryPolicy, error) {
+	var rb *hclsyntax.Block
+	if body := b.HclBlock().Body; body != nil {
+		for _, nb := range body.Blocks {
+			if nb.Type == retryBlockType {
+				rb = nb
+				break
+			}
+		}
+	}
+	if rb == nil {
+		if c, ok := b.Config().(interface{ baseConfig() *BaseConfig }); ok {
+			return c.baseConfig().retryPolicy, nil
+		}
+		return nil, nil
+	}
+	p := &RetryPolicy{
+		Attempts:   1,
+		Backoff:    defaultRetryBackoff,
+		MaxBackoff: defaultRetryMaxBackoff,
+	}
+	ctx := b.EvalContext()
+	for name, attr := range rb.Body.Attributes {
+		v, diag := attr.Expr.Value(ctx)
+		if diag.HasErrors() {
+			return nil, diag
+		}
+		var err error
+		switch name {
+		case "attempts":
+			err = gocty.FromCtyValue(v, &p.Attempts)
+			if err == nil && p.Attempts < 1 {
+				err = fmt.Errorf("must be at least 1")
+			}
+		case "backoff":
+			p.Backoff, err = ctyDuration(v)
+		case "max_backoff":
+			p.MaxBackoff, err = ctyDuration(v)
+		default:
+			err = fmt.Errorf("unsupported argument")
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid `%s` in `retry`, %s: %+v", name, attr.SrcRange.String(), err)
+		}
+	}
+	if p.MaxBackoff < p.Backoff {
+		return nil, fmt.Errorf("`max_backoff` must not be less than `backoff` in `retry`, %s", rb.Range().String())
+	}
+	return p, nil
+}
+
+func ctyDuration(v cty.Value) (time.Duration, error) {
+	if v.Type() != cty.String || v.IsNull() || !v.IsKnown() {
+		return 0, fmt.Errorf("must be a duration string like \"2s\"")
+	}
+	return time.ParseDuration(v.AsString())
+}
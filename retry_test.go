@@ -0,0 +1,190 @@
+package golden
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+var _ ApplyBlock = &FlakyResource{}
+
+// flakyFailures is how many times a FlakyResource with the name fails before it succeeds.
+var flakyFailures = map[string]int{}
+
+type FlakyResource struct {
+	*BaseBlock
+	*BaseResource
+}
+
+func (f *FlakyResource) Type() string {
+	return "flaky"
+}
+
+func (f *FlakyResource) ExecuteDuringPlan() error {
+	return f.run()
+}
+
+func (f *FlakyResource) Apply() error {
+	return f.run()
+}
+
+func (f *FlakyResource) run() error {
+	if flakyFailures[f.Name()] > 0 {
+		flakyFailures[f.Name()]--
+		return fmt.Errorf("%s is not ready", f.Name())
+	}
+	return nil
+}
+
+type retrySuite struct {
+	suite.Suite
+	*testBase
+	delays []time.Duration
+}
+
+func TestRetrySuite(t *testing.T) {
+	suite.Run(t, new(retrySuite))
+}
+
+func (s *retrySuite) SetupTest() {
+	s.testBase = newTestBase()
+	s.delays = nil
+	flakyFailures = map[string]int{}
+	s.stub.Stub(&retrySleep, func(ctx context.Context, d time.Duration) error {
+		s.delays = append(s.delays, d)
+		return ctx.Err()
+	})
+}
+
+func (s *retrySuite) TearDownTest() {
+	s.teardown()
+}
+
+func (s *retrySuite) config(ctx context.Context, policy *RetryPolicy, config string) *DummyConfig {
+	s.dummyFsWithFiles(map[string]string{
+		"/main.hcl": config,
+	})
+	hclBlocks, err := loadHclBlocks(false, "/")
+	require.NoError(s.T(), err)
+	c := &DummyConfig{
+		BaseConfig: NewBasicConfigFromArgs(NewBaseConfigArgs{
+			Basedir:         "/",
+			DslFullName:     "faketerraform",
+			DslAbbreviation: "ft",
+			Ctx:             ctx,
+			RetryPolicy:     policy,
+		}),
+	}
+	require.NoError(s.T(), InitConfig(c, hclBlocks))
+	return c
+}
+
+func (s *retrySuite) TestRetry_MetaBlock() {
+	c := s.config(context.Background(), nil, `resource "flaky" "foo" {
+  retry {
+    attempts    = 5
+    backoff     = "2s"
+    max_backoff = "5s"
+  }
+}
+`)
+	flakyFailures["foo"] = 3
+	require.NoError(s.T(), c.RunPlan())
+	s.Equal([]time.Duration{2 * time.Second, 4 * time.Second, 5 * time.Second}, s.delays)
+	foo := Blocks[*FlakyResource](c)[0]
+	s.Equal(4, foo.PlanAttempts())
+	s.Equal(4, c.PlanResult().Blocks[0].Attempts)
+
+	flakyFailures["foo"] = 1
+	require.NoError(s.T(), c.RunApply())
+	s.Equal(2, foo.ApplyAttempts())
+}
+
+func (s *retrySuite) TestRetry_GiveUpAfterAttempts() {
+	c := s.config(context.Background(), nil, `resource "flaky" "foo" {
+  retry {
+    attempts = 2
+  }
+}
+`)
+	flakyFailures["foo"] = 3
+	err := c.RunPlan()
+	s.ErrorContains(err, "failed after 2 attempts: foo is not ready")
+	s.Equal([]time.Duration{time.Second}, s.delays)
+}
+
+func (s *retrySuite) TestRetry_DefaultPolicyAndNoRetry() {
+	c := s.config(context.Background(), &RetryPolicy{Attempts: 3, Backoff: time.Millisecond, MaxBackoff: time.Second}, `resource "flaky" "foo" {
+}
+`)
+	flakyFailures["foo"] = 2
+	s.NoError(c.RunPlan())
+	s.Equal(3, Blocks[*FlakyResource](c)[0].PlanAttempts())
+	s.Equal([]time.Duration{time.Millisecond, 2 * time.Millisecond}, s.delays)
+
+	s.delays = nil
+	c = s.config(context.Background(), nil, `resource "flaky" "foo" {
+}
+`)
+	flakyFailures["foo"] = 1
+	err := c.RunPlan()
+	s.ErrorContains(err, "foo is not ready")
+	s.NotContains(err.Error(), "attempts")
+	s.Empty(s.delays)
+}
+
+func (s *retrySuite) TestRetry_PolicyWithoutMaxBackoff() {
+	c := s.config(context.Background(), &RetryPolicy{Attempts: 4, Backoff: 2 * time.Second}, `resource "flaky" "foo" {
+}
+`)
+	flakyFailures["foo"] = 3
+	s.NoError(c.RunPlan())
+	s.Equal([]time.Duration{2 * time.Second, 4 * time.Second, 8 * time.Second}, s.delays)
+	s.Equal(time.Duration(math.MaxInt64/2+1), (&RetryPolicy{Backoff: 1}).delay(100))
+}
+
+func (s *retrySuite) TestRetry_ContextCancelled() {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	c := s.config(ctx, nil, `resource "flaky" "foo" {
+  retry {
+    attempts = 3
+  }
+}
+`)
+	flakyFailures["foo"] = 3
+	err := c.RunPlan()
+	s.ErrorContains(err, "retry cancelled after 1 attempts: context canceled, last error: foo is not ready")
+	s.Equal(1, Blocks[*FlakyResource](c)[0].PlanAttempts())
+}
+
+func (s *retrySuite) TestRetry_InvalidBlock() {
+	cases := map[string]string{
+		"invalid `attempts` in `retry`":                 `attempts = 0`,
+		"invalid `backoff` in `retry`":                  `backoff = 1`,
+		"invalid `foo` in `retry`":                      `foo = 1`,
+		"`max_backoff` must not be less than `backoff`": "backoff = \"10s\"\n    max_backoff = \"1s\"",
+	}
+	for want, body := range cases {
+		s.Run(want, func() {
+			c := s.config(context.Background(), nil, `resource "flaky" "foo" {
+  retry {
+    `+body+`
+  }
+}
+`)
+			s.ErrorContains(c.RunPlan(), want)
+		})
+	}
+}
+
+func TestRetrySleep_Cancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	require.ErrorIs(t, retrySleep(ctx, time.Hour), context.Canceled)
+}
@@ -61,3 +61,18 @@ func name(t hcl.Traverser) string {
 		}
 	}
 }
+
+func traversalString(t hcl.Traversal) string {
+	sb := strings.Builder{}
+	for i, traverser := range t {
+		if index, ok := traverser.(hcl.TraverseIndex); ok {
+			fmt.Fprintf(&sb, `[%s]`, CtyValueToString(index.Key))
+			continue
+		}
+		if i > 0 {
+			sb.WriteString(".")
+		}
+		sb.WriteString(name(traverser))
+	}
+	return sb.String()
+}
@@ -0,0 +1,247 @@
+package golden
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/bmatcuk/doublestar"
+	"github.com/spf13/afero"
+	"github.com/zclconf/go-cty/cty"
+	"github.com/zclconf/go-cty/cty/function"
+)
+
+// maxSymlinkHops is the same limit as most unix kernels, to stop symlink loops.
+const maxSymlinkHops = 40
+
+// Sandbox confines filesystem functions like `file` and `fileset` to AllowedRoots, files are read through the config's filesystem.
+// AllowedRoots defaults to the base dir of the config.
+type Sandbox struct {
+	AllowedRoots []string
+}
+
+// EnableSandbox replaces filesystem functions with sandboxed ones that can only access allowedRoots.
+func (c *BaseConfig) EnableSandbox(allowedRoots ...string) {
+	c.sandbox = &Sandbox{
+		AllowedRoots: allowedRoots,
+	}
+}
+
+func (c *BaseConfig) sandboxFunctions() map[string]function.Function {
+	if c.sandbox == nil {
+		return nil
+	}
+	s := sandboxFs{
+		fs:      configFs,
+		basedir: c.basedir,
+		roots:   c.sandboxRoots(),
+	}
+	return map[string]function.Function{
+		"file":       s.fileFunc(),
+		"fileexists": s.fileExistsFunc(),
+		"fileset":    s.fileSetFunc(),
+	}
+}
+
+func (c *BaseConfig) sandboxRoots() []string {
+	if len(c.sandbox.AllowedRoots) == 0 {
+		return []string{c.basedir}
+	}
+	return c.sandbox.AllowedRoots
+}
+
+type sandboxFs struct {
+	fs      afero.Fs
+	basedir string
+	roots   []string
+}
+
+// resolve returns the real path of path with all symlinks resolved, it's an error if the real path is outside of roots.
+func (s sandboxFs) resolve(path string) (string, error) {
+	realPath, err := s.realPath(path)
+	if err != nil {
+		return "", err
+	}
+	for _, root := range s.roots {
+		realRoot, err := s.realPath(root)
+		if err != nil {
+			return "", err
+		}
+		if rel, err := filepath.Rel(realRoot, realPath); err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			return realPath, nil
+		}
+	}
+	return "", fmt.Errorf("path %q escapes sandbox, it resolves to %q which is outside of allowed roots %v", path, realPath, s.roots)
+}
+
+func (s sandboxFs) realPath(path string) (string, error) {
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(s.basedir, path)
+	}
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return "", err
+	}
+	lstater, canLstat := s.fs.(afero.Lstater)
+	linkReader, canReadLink := s.fs.(afero.LinkReader)
+	if !canLstat || !canReadLink {
+		return abs, nil
+	}
+	root := filepath.VolumeName(abs) + string(filepath.Separator)
+	pending := splitPath(strings.TrimPrefix(abs, root))
+	resolved := root
+	hops := 0
+	for len(pending) > 0 {
+		next := filepath.Join(resolved, pending[0])
+		pending = pending[1:]
+		fi, lstatCalled, err := lstater.LstatIfPossible(next)
+		if err != nil || !lstatCalled || fi.Mode()&os.ModeSymlink == 0 {
+			resolved = next
+			continue
+		}
+		if hops++; hops > maxSymlinkHops {
+			return "", fmt.Errorf("too many levels of symbolic links in %q", path)
+		}
+		target, err := linkReader.ReadlinkIfPossible(next)
+		if err != nil {
+			return "", err
+		}
+		if filepath.IsAbs(target) {
+			resolved = filepath.VolumeName(target) + string(filepath.Separator)
+			target = strings.TrimPrefix(target, resolved)
+		}
+		pending = append(splitPath(target), pending...)
+	}
+	return resolved, nil
+}
+
+func splitPath(path string) []string {
+	var r []string
+	for _, p := range strings.Split(filepath.Clean(path), string(filepath.Separator)) {
+		if p != "" && p != "." {
+			r = append(r, p)
+		}
+	}
+	return r
+}
+
+func (s sandboxFs) fileFunc() function.Function {
+	return function.New(&function.Spec{
+		Description: "Reads the contents of a file inside the sandbox and returns them as a string.",
+		Params: []function.Parameter{
+			{
+				Name: "path",
+				Type: cty.String,
+			},
+		},
+		Type: function.StaticReturnType(cty.String),
+		Impl: func(args []cty.Value, retType cty.Type) (cty.Value, error) {
+			path := args[0].AsString()
+			realPath, err := s.resolve(path)
+			if err != nil {
+				return cty.UnknownVal(cty.String), function.NewArgError(0, err)
+			}
+			src, err := afero.ReadFile(s.fs, realPath)
+			if err != nil {
+				return cty.UnknownVal(cty.String), fmt.Errorf("failed to read %s: %+v", path, err)
+			}
+			if !utf8.Valid(src) {
+				return cty.UnknownVal(cty.String), fmt.Errorf("contents of %s are not valid UTF-8", path)
+			}
+			return cty.StringVal(string(src)), nil
+		},
+	})
+}
+
+func (s sandboxFs) fileExistsFunc() function.Function {
+	return function.New(&function.Spec{
+		Description: "Determines whether a file exists inside the sandbox at a given path.",
+		Params: []function.Parameter{
+			{
+				Name: "path",
+				Type: cty.String,
+			},
+		},
+		Type: function.StaticReturnType(cty.Bool),
+		Impl: func(args []cty.Value, retType cty.Type) (cty.Value, error) {
+			path := args[0].AsString()
+			realPath, err := s.resolve(path)
+			if err != nil {
+				return cty.UnknownVal(cty.Bool), function.NewArgError(0, err)
+			}
+			fi, err := s.fs.Stat(realPath)
+			if err != nil {
+				if errors.Is(err, os.ErrNotExist) {
+					return cty.False, nil
+				}
+				return cty.UnknownVal(cty.Bool), fmt.Errorf("failed to stat %s", path)
+			}
+			if fi.Mode().IsRegular() {
+				return cty.True, nil
+			}
+			return cty.False, fmt.Errorf("%s is not a regular file, but %q", path, fi.Mode().String())
+		},
+	})
+}
+
+func (s sandboxFs) fileSetFunc() function.Function {
+	return function.New(&function.Spec{
+		Description: "Enumerates a set of regular file names inside the sandbox given a path and pattern.",
+		Params: []function.Parameter{
+			{
+				Name: "path",
+				Type: cty.String,
+			},
+			{
+				Name: "pattern",
+				Type: cty.String,
+			},
+		},
+		Type: function.StaticReturnType(cty.Set(cty.String)),
+		Impl: func(args []cty.Value, retType cty.Type) (cty.Value, error) {
+			path := args[0].AsString()
+			pattern := filepath.FromSlash(args[1].AsString())
+			realPath, err := s.resolve(path)
+			if err != nil {
+				return cty.UnknownVal(retType), function.NewArgError(0, err)
+			}
+			var matches []cty.Value
+			err = afero.Walk(s.fs, realPath, func(p string, info os.FileInfo, err error) error {
+				if err != nil {
+					return err
+				}
+				rel, err := filepath.Rel(realPath, p)
+				if err != nil {
+					return err
+				}
+				matched, err := doublestar.PathMatch(pattern, rel)
+				if err != nil {
+					return fmt.Errorf("failed to match pattern (%s): %+v", pattern, err)
+				}
+				if !matched || info.IsDir() {
+					return nil
+				}
+				// matched symlinks must point to regular files in the sandbox too.
+				target, err := s.resolve(p)
+				if err != nil {
+					return err
+				}
+				if fi, err := s.fs.Stat(target); err != nil || !fi.Mode().IsRegular() {
+					return nil
+				}
+				matches = append(matches, cty.StringVal(filepath.ToSlash(rel)))
+				return nil
+			})
+			if err != nil {
+				return cty.UnknownVal(retType), err
+			}
+			if len(matches) == 0 {
+				return cty.SetValEmpty(cty.String), nil
+			}
+			return cty.SetVal(matches), nil
+		},
+	})
+}
@@ -0,0 +1,147 @@
+package golden
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/prashantv/gostub"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"github.com/zclconf/go-cty/cty"
+	"github.com/zclconf/go-cty/cty/function"
+)
+
+type sandboxSuite struct {
+	suite.Suite
+	*testBase
+}
+
+func TestSandboxSuite(t *testing.T) {
+	suite.Run(t, new(sandboxSuite))
+}
+
+func (s *sandboxSuite) SetupTest() {
+	s.testBase = newTestBase()
+}
+
+func (s *sandboxSuite) TearDownTest() {
+	s.teardown()
+}
+
+func (s *sandboxSuite) sandboxConfig(basedir string, roots ...string) *BaseConfig {
+	c := NewBasicConfigFromArgs(NewBaseConfigArgs{
+		Basedir:         basedir,
+		DslFullName:     "faketerraform",
+		DslAbbreviation: "ft",
+		Sandbox: &Sandbox{
+			AllowedRoots: roots,
+		},
+	})
+	return c
+}
+
+func (s *sandboxSuite) call(c *BaseConfig, name string, args ...cty.Value) (cty.Value, error) {
+	return c.EmptyEvalContext().Functions[name].Call(args)
+}
+
+func (s *sandboxSuite) TestSandbox_ReadFilesThroughConfigFs() {
+	s.dummyFsWithFiles(map[string]string{
+		"/cfg/main.hcl":        `locals {}`,
+		"/cfg/files/a.txt":     "a",
+		"/cfg/files/sub/b.txt": "b",
+		"/shared/c.txt":        "c",
+	})
+	c := s.sandboxConfig("/cfg", "/cfg", "/shared")
+	v, err := s.call(c, "file", cty.StringVal("files/a.txt"))
+	require.NoError(s.T(), err)
+	s.Equal(cty.StringVal("a"), v)
+	v, err = s.call(c, "file", cty.StringVal("/shared/c.txt"))
+	require.NoError(s.T(), err)
+	s.Equal(cty.StringVal("c"), v)
+	v, err = s.call(c, "fileexists", cty.StringVal("files/missing.txt"))
+	require.NoError(s.T(), err)
+	s.Equal(cty.False, v)
+	v, err = s.call(c, "fileset", cty.StringVal("files"), cty.StringVal("**/*.txt"))
+	require.NoError(s.T(), err)
+	s.Equal(cty.SetVal([]cty.Value{cty.StringVal("a.txt"), cty.StringVal("sub/b.txt")}), v)
+}
+
+func (s *sandboxSuite) TestSandbox_OverridesCannotReplaceSandboxedFunctions() {
+	s.dummyFsWithFiles(map[string]string{
+		"/cfg/a.txt":    "a",
+		"/secret/b.txt": "b",
+	})
+	c := s.sandboxConfig("/cfg")
+	require.NoError(s.T(), c.OverrideBuiltinFunction("file", function.New(&function.Spec{
+		Params: []function.Parameter{{Name: "path", Type: cty.String}},
+		Type:   function.StaticReturnType(cty.String),
+		Impl: func(args []cty.Value, retType cty.Type) (cty.Value, error) {
+			return cty.StringVal("escaped"), nil
+		},
+	})))
+	_, err := s.call(c, "file", cty.StringVal("/secret/b.txt"))
+	s.ErrorContains(err, "outside")
+	v, err := s.call(c, "file", cty.StringVal("a.txt"))
+	require.NoError(s.T(), err)
+	s.Equal(cty.StringVal("a"), v)
+	for _, sig := range c.ListFunctions() {
+		if sig.Name == "file" {
+			s.Equal(FunctionSourceSandbox, sig.Source)
+		}
+	}
+}
+
+func (s *sandboxSuite) TestSandbox_PathEscapeShouldFail() {
+	s.dummyFsWithFiles(map[string]string{
+		"/cfg/main.hcl": `locals {
+  secret = file("../secret.txt")
+}
+`,
+		"/secret.txt": "secret",
+	})
+	c := s.sandboxConfig("/cfg")
+	for _, f := range []string{"file", "fileexists"} {
+		_, err := s.call(c, f, cty.StringVal("../secret.txt"))
+		s.ErrorContains(err, `path "../secret.txt" escapes sandbox, it resolves to "/secret.txt" which is outside of allowed roots [/cfg]`)
+	}
+	_, err := s.call(c, "fileset", cty.StringVal("/"), cty.StringVal("*"))
+	s.ErrorContains(err, "escapes sandbox")
+
+	blocks, err := LoadHclBlocks(false, "/cfg")
+	require.NoError(s.T(), err)
+	err = InitConfig(c, blocks)
+	require.NotNil(s.T(), err)
+	s.Contains(err.Error(), "/cfg/main.hcl:2,")
+	s.Contains(err.Error(), "escapes sandbox")
+}
+
+func (s *sandboxSuite) TestSandbox_SymlinkEscapeShouldFail() {
+	dir := s.T().TempDir()
+	cfg := filepath.Join(dir, "cfg")
+	require.NoError(s.T(), os.MkdirAll(filepath.Join(cfg, "inner"), 0755))
+	require.NoError(s.T(), os.WriteFile(filepath.Join(dir, "secret.txt"), []byte("secret"), 0600))
+	require.NoError(s.T(), os.WriteFile(filepath.Join(cfg, "inner", "ok.txt"), []byte("ok"), 0600))
+	require.NoError(s.T(), os.Symlink(filepath.Join(dir, "secret.txt"), filepath.Join(cfg, "inner", "abs_link.txt")))
+	require.NoError(s.T(), os.Symlink("../../secret.txt", filepath.Join(cfg, "inner", "rel_link.txt")))
+	require.NoError(s.T(), os.Symlink("inner", filepath.Join(cfg, "inner_link")))
+	stub := gostub.Stub(&configFs, afero.NewOsFs())
+	defer stub.Reset()
+
+	c := s.sandboxConfig(cfg)
+	v, err := s.call(c, "file", cty.StringVal("inner_link/ok.txt"))
+	require.NoError(s.T(), err)
+	s.Equal(cty.StringVal("ok"), v)
+	for _, link := range []string{"inner/abs_link.txt", "inner/rel_link.txt", "inner_link/rel_link.txt"} {
+		_, err = s.call(c, "file", cty.StringVal(link))
+		s.ErrorContains(err, "escapes sandbox", link)
+	}
+	_, err = s.call(c, "fileset", cty.StringVal("inner"), cty.StringVal("*.txt"))
+	s.ErrorContains(err, "escapes sandbox")
+}
+
+func (s *sandboxSuite) TestSandbox_DisabledByDefault() {
+	c := NewBasicConfig("/cfg", "faketerraform", "ft", nil, nil, nil)
+	s.Nil(c.sandboxFunctions())
+}
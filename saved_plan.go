@@ -0,0 +1,381 @@
+package golden
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"sort"
+
+	"github.com/spf13/afero"
+	"github.com/zclconf/go-cty/cty"
+	ctyjson "github.com/zclconf/go-cty/cty/json"
+)
+
+// SavedPlanFormatVersion is the version of saved plan files, files with other versions are refused.
+const SavedPlanFormatVersion = "1.0"
+
+// SavedPlan is the content of a plan file written by SavePlan, it could be applied later by LoadPlan and RunApply.
+type SavedPlan struct {
+	FormatVersion string `json:"format_version"`
+	Workspace     string `json:"workspace"`
+	// SourceHash is the hash of config files and var files, a plan cannot be loaded once these files changed.
+	SourceHash string                `json:"source_hash"`
+	Variables  map[string]TypedValue `json:"variables"`
+	// StateSerial is the serial of prior state when the plan was made, the plan cannot be loaded once state changed.
+	StateSerial int `json:"state_serial,omitempty"`
+	// Order is the order in which blocks were planned.
+	Order  []string     `json:"order"`
+	Blocks []SavedBlock `json:"blocks"`
+}
+
+type SavedBlock struct {
+	Address string                `json:"address"`
+	Id      string                `json:"id"`
+	Values  map[string]TypedValue `json:"values"`
+	// Module is the saved plan of the child config of a `module` block.
+	Module *SavedPlan `json:"module,omitempty"`
+}
+
+// TypedValue is a cty.Value serialised with its type, so it could be restored exactly.
+// Sensitive values are written in clear text with their paths in `sensitive_paths`, and marked as sensitive again when restored.
+type TypedValue struct {
+	cty.Value
+}
+
+func (v TypedValue) MarshalJSON() ([]byte, error) {
+	t, err := ctyjson.MarshalType(v.Type())
+	if err != nil {
+		return nil, err
+	}
+	unmarked, paths, err := sensitivePaths(v.Value)
+	if err != nil {
+		return nil, err
+	}
+	value, err := ctyjson.Marshal(unmarked, v.Type())
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(struct {
+		Type           json.RawMessage `json:"type"`
+		Value          json.RawMessage `json:"value"`
+		SensitivePaths [][]pathStep    `json:"sensitive_paths,omitempty"`
+	}{
+		Type:           t,
+		Value:          value,
+		SensitivePaths: paths,
+	})
+}
+
+func (v *TypedValue) UnmarshalJSON(data []byte) error {
+	var raw struct {
+		Type           json.RawMessage `json:"type"`
+		Value          json.RawMessage `json:"value"`
+		SensitivePaths [][]pathStep    `json:"sensitive_paths"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	t, err := ctyjson.UnmarshalType(raw.Type)
+	if err != nil {
+		return err
+	}
+	value, err := ctyjson.Unmarshal(raw.Value, t)
+	if err != nil {
+		return err
+	}
+	v.Value, err = markSensitive(value, raw.SensitivePaths)
+	return err
+}
+
+// SavePlan writes the planned config to path, it must be called after RunPlan succeeded.
+// A relative path like `plan.json` is resolved under WorkspaceDir, so workspaces never share plan files.
+func (c *BaseConfig) SavePlan(path string) error {
+	path, err := c.workspacePath(path)
+	if err != nil {
+		return err
+	}
+	p, err := c.savedPlan()
+	if err != nil {
+		return err
+	}
+	content, err := json.MarshalIndent(p, "", "  ")
+	if err != nil {
+		return fmt.Errorf("cannot marshal plan: %+v", err)
+	}
+	if err = configFs.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return afero.WriteFile(configFs, path, content, 0600)
+}
+
+func (c *BaseConfig) savedPlan() (*SavedPlan, error) {
+	if c.planErrors == nil {
+		return nil, fmt.Errorf("config has not been planned")
+	}
+	for _, address := range c.planOrder {
+		if err := c.planErrors[address]; err != nil {
+			return nil, fmt.Errorf("cannot save a failed plan, %s: %+v", address, err)
+		}
+	}
+	hash, err := c.SourceHash()
+	if err != nil {
+		return nil, err
+	}
+	p := &SavedPlan{
+		FormatVersion: SavedPlanFormatVersion,
+		Workspace:     c.Workspace(),
+		SourceHash:    hash,
+		Variables:     make(map[string]TypedValue),
+		Order:         slices.Clone(c.planOrder),
+	}
+	if c.priorState != nil {
+		p.StateSerial = c.priorState.Serial
+	}
+	bs := blocks(c)
+	sort.Slice(bs, func(i, j int) bool {
+		return bs[i].Address() < bs[j].Address()
+	})
+	for _, b := range bs {
+		sb := SavedBlock{
+			Address: b.Address(),
+			Id:      b.Id(),
+			Values:  make(map[string]TypedValue),
+		}
+		values := Value(b)
+		if v, ok := b.(*VariableBlock); ok {
+			values = map[string]cty.Value{
+				"value": v.Value(),
+			}
+			p.Variables[v.Name()] = TypedValue{v.Value()}
+		}
+		for n, v := range values {
+			if v == cty.NilVal {
+				continue
+			}
+			if !v.IsWhollyKnown() {
+				return nil, fmt.Errorf("cannot save %s, `%s` is unknown", b.Address(), n)
+			}
+			sb.Values[n] = TypedValue{v}
+		}
+		if m, ok := b.(*ModuleBlock); ok && m.child != nil {
+			if sb.Module, err = m.child.savedPlan(); err != nil {
+				return nil, fmt.Errorf("%s: %+v", m.Address(), err)
+			}
+		}
+		p.Blocks = append(p.Blocks, sb)
+	}
+	return p, nil
+}
+
+// LoadPlan restores a plan file written by SavePlan into the config, so RunApply could be called without RunPlan.
+// The config must be initialized from the same files, it's an error if config files or var files changed since the plan was saved.
+// Initialization resolves variables, set NewBaseConfigArgs.SavedPlanPath to read them from the plan file rather than from inputs.
+// A relative path is resolved under WorkspaceDir like SavePlan.
+func (c *BaseConfig) LoadPlan(path string) error {
+	path, err := c.workspacePath(path)
+	if err != nil {
+		return err
+	}
+	p, err := readSavedPlan(path)
+	if err != nil {
+		return err
+	}
+	return c.restorePlan(p)
+}
+
+func readSavedPlan(path string) (*SavedPlan, error) {
+	content, err := afero.ReadFile(configFs, path)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read plan %s: %+v", path, err)
+	}
+	var p SavedPlan
+	if err = json.Unmarshal(content, &p); err != nil {
+		return nil, fmt.Errorf("cannot parse plan %s: %+v", path, err)
+	}
+	return &p, nil
+}
+
+func (c *BaseConfig) readVariablesFromSavedPlan(path string) (map[string]VariableValueRead, error) {
+	p, err := readSavedPlan(path)
+	if err != nil {
+		return nil, err
+	}
+	reads := make(map[string]VariableValueRead)
+	for n, v := range p.Variables {
+		value := v.Value
+		reads[n] = NewVariableValueRead(n, &value, nil).withSource(VariableValueSource{
+			Type:     VariableValueSourceSavedPlan,
+			FileName: path,
+		})
+	}
+	return reads, nil
+}
+
+func (c *BaseConfig) restorePlan(p *SavedPlan) error {
+	if p.FormatVersion != SavedPlanFormatVersion {
+		return fmt.Errorf("unsupported plan format version %s, want %s", p.FormatVersion, SavedPlanFormatVersion)
+	}
+	if p.Workspace != c.Workspace() {
+		return fmt.Errorf("plan was saved for workspace %s, current workspace is %s", p.Workspace, c.Workspace())
+	}
+	hash, err := c.SourceHash()
+	if err != nil {
+		return err
+	}
+	if hash != p.SourceHash {
+		return fmt.Errorf("configuration at %s has changed since the plan was saved, please plan again", c.basedir)
+	}
+	if err = c.loadPriorState(); err != nil {
+		return err
+	}
+	if c.priorState != nil && c.priorState.Serial != p.StateSerial {
+		return fmt.Errorf("state %s has been changed by another run since the plan was saved, please plan again", c.statePath)
+	}
+	saved := make(map[string]SavedBlock)
+	for _, sb := range p.Blocks {
+		saved[sb.Address] = sb
+	}
+	restored := make(map[string]struct{})
+	c.planErrors = make(map[string]error)
+	c.planOrder = nil
+	err = c.runDag(func(b Block) error {
+		sb, ok := saved[b.Address()]
+		if !ok {
+			return fmt.Errorf("%s is not in the saved plan, please plan again", b.Address())
+		}
+		err := restoreBlock(b, sb)
+		c.planErrors[b.Address()] = err
+		c.planOrder = append(c.planOrder, b.Address())
+		if err != nil {
+			return fmt.Errorf("cannot restore %s: %+v", b.Address(), err)
+		}
+		restored[b.Address()] = struct{}{}
+		b.markReady()
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	for _, sb := range p.Blocks {
+		if _, ok := restored[sb.Address]; !ok {
+			return fmt.Errorf("%s in the saved plan is not in the configuration, please plan again", sb.Address)
+		}
+	}
+	return c.verifyPlanOrder(p.Order)
+}
+
+// verifyPlanOrder ensures the saved order covers every block and plans every block after its upstreams in the restored dag.
+func (c *BaseConfig) verifyPlanOrder(order []string) error {
+	positions := make(map[string]int)
+	for i, address := range order {
+		if _, ok := positions[address]; !ok {
+			positions[address] = i
+		}
+	}
+	for _, address := range sortedKeys(c.d.GetVertices()) {
+		position, ok := positions[address]
+		if !ok {
+			return fmt.Errorf("%s is not in the saved plan order, please plan again", address)
+		}
+		ancestors, err := c.d.GetAncestors(address)
+		if err != nil {
+			return err
+		}
+		for _, upstream := range sortedKeys(ancestors) {
+			if p, ok := positions[upstream]; !ok || p > position {
+				return fmt.Errorf("saved plan order is inconsistent with the configuration, %s is planned before its upstream %s, please plan again", address, upstream)
+			}
+		}
+	}
+	for _, address := range sortedKeys(positions) {
+		if _, err := c.d.GetVertex(address); err != nil {
+			return fmt.Errorf("%s in the saved plan order is not in the configuration, please plan again", address)
+		}
+	}
+	return nil
+}
+
+func restoreBlock(b Block, sb SavedBlock) error {
+	if bb, ok := b.(interface{ restoreId(string) }); ok {
+		bb.restoreId(sb.Id)
+	}
+	switch t := b.(type) {
+	case *VariableBlock:
+		v := sb.Values["value"].Value
+		t.variableValue = &v
+		return nil
+	case *ModuleBlock:
+		if err := Decode(t); err != nil {
+			return err
+		}
+		if sb.Module == nil {
+			return fmt.Errorf("saved module plan is missing")
+		}
+		child, err := t.loadChild()
+		if err != nil {
+			return err
+		}
+		if err = child.restorePlan(sb.Module); err != nil {
+			return err
+		}
+		t.child = child
+		return nil
+	}
+	values := make(map[string]cty.Value)
+	for n, v := range sb.Values {
+		values[n] = v.Value
+	}
+	return restoreValues(b, values)
+}
+
+// restoreValues sets fields of b from values, which are keyed by names of `hcl` or `attribute` tags like Value(b) returns.
+func restoreValues(b Block, values map[string]cty.Value) error {
+	bv := reflect.ValueOf(b).Elem()
+	for i := 0; i < bv.NumField(); i++ {
+		name, tagged := fieldName(bv.Type().Field(i))
+		v, ok := values[name]
+		if !tagged || !ok || !bv.Field(i).CanSet() {
+			continue
+		}
+		if err := fromCtyValue(v, bv.Field(i)); err != nil {
+			return fmt.Errorf("%s: %+v", name, err)
+		}
+	}
+	return nil
+}
+
+// SourceHash returns sha256 of `*.hcl` files in config directories and var files, child modules are hashed in their own plans.
+func (c *BaseConfig) SourceHash() (string, error) {
+	var files []string
+	for _, dir := range c.Layers() {
+		matches, err := afero.Glob(configFs, filepath.Join(dir, "*.hcl"))
+		if err != nil {
+			return "", err
+		}
+		files = append(files, matches...)
+	}
+	varFiles, err := afero.Glob(configFs, filepath.Join(c.variableConfigFilesDir(), fmt.Sprintf("*.%svars*", c.dslAbbreviation)))
+	if err != nil {
+		return "", err
+	}
+	files = append(files, varFiles...)
+	sort.Strings(files)
+	h := sha256.New()
+	for i, f := range files {
+		if i > 0 && files[i-1] == f {
+			continue
+		}
+		content, err := afero.ReadFile(configFs, f)
+		if err != nil && !os.IsNotExist(err) {
+			return "", err
+		}
+		_, _ = fmt.Fprintf(h, "%s\x00%d\x00", f, len(content))
+		_, _ = h.Write(content)
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
@@ -0,0 +1,242 @@
+package golden
+
+import (
+	"encoding/json"
+	"slices"
+	"testing"
+
+	"github.com/lonegunmanb/hclfuncs/marks"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"github.com/zclconf/go-cty/cty"
+)
+
+type savedPlanSuite struct {
+	suite.Suite
+	*testBase
+}
+
+func TestSavedPlanSuite(t *testing.T) {
+	suite.Run(t, new(savedPlanSuite))
+}
+
+func (s *savedPlanSuite) SetupTest() {
+	s.testBase = newTestBase()
+}
+
+func (s *savedPlanSuite) TearDownTest() {
+	s.teardown()
+}
+
+const savedPlanConfig = `variable "env" {
+  type = string
+}
+
+locals {
+  keys = ["a", "b"]
+}
+
+resource "dummy" "foo" {
+  for_each = toset(local.keys)
+  tags = {
+    env = var.env
+    key = each.value
+  }
+  nested_block {
+    id   = 1
+    name = "n"
+  }
+}
+
+resource "dummy" "bar" {
+  tags = {
+    foo_id = resource.dummy.foo["a"].id
+  }
+}
+`
+
+func (s *savedPlanSuite) dummyConfig(env string) *DummyConfig {
+	config, err := BuildDummyConfig("/", "/", []CliFlagAssignedVariables{
+		NewCliFlagAssignedVariable("env", env),
+	}, nil)
+	require.NoError(s.T(), err)
+	return config.(*DummyConfig)
+}
+
+func (s *savedPlanSuite) savedPlanConfig(planPath string) *DummyConfig {
+	hclBlocks, err := loadHclBlocks(false, "/")
+	require.NoError(s.T(), err)
+	c := &DummyConfig{
+		BaseConfig: NewBasicConfigFromArgs(NewBaseConfigArgs{
+			Basedir:         "/",
+			DslFullName:     "faketerraform",
+			DslAbbreviation: "ft",
+			SavedPlanPath:   planPath,
+		}),
+	}
+	require.NoError(s.T(), InitConfig(c, hclBlocks))
+	return c
+}
+
+func (s *savedPlanSuite) resource(c Config, address string) *DummyResource {
+	for _, r := range Blocks[*DummyResource](c) {
+		if r.Address() == address {
+			return r
+		}
+	}
+	s.T().Fatalf("%s not found", address)
+	return nil
+}
+
+func (s *savedPlanSuite) TestSavedPlan_ApplyWithoutReplan() {
+	s.dummyFsWithFiles(map[string]string{
+		"/main.hcl": savedPlanConfig,
+	})
+	planned := s.dummyConfig("prod")
+	require.NoError(s.T(), planned.RunPlan())
+	require.NoError(s.T(), planned.SavePlan("/.faketerraform/plan.json"))
+
+	// variables and ids come from the plan file, the apply job needs no variable inputs.
+	applied := s.savedPlanConfig("/.faketerraform/plan.json")
+	explanation, err := applied.ExplainVariable("env")
+	require.NoError(s.T(), err)
+	s.Equal(VariableValueSourceSavedPlan, explanation.Source.Type)
+	require.NoError(s.T(), applied.LoadPlan("/.faketerraform/plan.json"))
+	for _, address := range []string{"resource.dummy.foo[a]", "resource.dummy.foo[b]", "resource.dummy.bar"} {
+		want := s.resource(planned, address)
+		got := s.resource(applied, address)
+		s.Equal(want.Tags, got.Tags, address)
+		s.Equal(want.NestedBlocks, got.NestedBlocks, address)
+		s.Equal(want.Id(), got.Id(), address)
+	}
+	s.Equal("prod", s.resource(applied, "resource.dummy.foo[a]").Tags["env"])
+	s.Equal(cty.StringVal("prod"), applied.EvalContext().Variables["var"].GetAttr("env"))
+	s.NoError(applied.RunApply())
+}
+
+func (s *savedPlanSuite) TestSavedPlan_RefuseChangedConfig() {
+	s.dummyFsWithFiles(map[string]string{
+		"/main.hcl": savedPlanConfig,
+	})
+	planned := s.dummyConfig("prod")
+	require.NoError(s.T(), planned.RunPlan())
+	require.NoError(s.T(), planned.SavePlan("/plan.json"))
+
+	require.NoError(s.T(), afero.WriteFile(s.fs, "/dev.ftvars", []byte(`env = "dev"`), 0644))
+	applied := s.dummyConfig("prod")
+	err := applied.LoadPlan("/plan.json")
+	s.ErrorContains(err, "has changed since the plan was saved")
+}
+
+func (s *savedPlanSuite) TestSavedPlan_RefuseFailedPlan() {
+	s.dummyFsWithFiles(map[string]string{
+		"/main.hcl": `resource "dummy" "foo" {
+  precondition {
+    condition     = false
+    error_message = "not ready"
+  }
+}
+`,
+	})
+	config, err := BuildDummyConfig("/", "/", nil, nil)
+	require.NoError(s.T(), err)
+	s.Error(config.RunPlan())
+	s.ErrorContains(config.(*DummyConfig).SavePlan("/plan.json"), "cannot save a failed plan")
+	unplanned, err := BuildDummyConfig("/", "/", nil, nil)
+	require.NoError(s.T(), err)
+	s.ErrorContains(unplanned.(*DummyConfig).SavePlan("/plan.json"), "config has not been planned")
+}
+
+func (s *savedPlanSuite) TestSavedPlan_Module() {
+	s.dummyFsWithFiles(map[string]string{
+		"/main.hcl": `module "child" {
+  source = "./child"
+  env    = "prod"
+}
+
+resource "dummy" "foo" {
+  tags = {
+    env = module.child.env
+  }
+}
+`,
+		"/child/main.hcl": `variable "env" {}
+
+resource "dummy" "bar" {
+  tags = {
+    env = var.env
+  }
+}
+
+output "env" {
+  value = resource.dummy.bar.tags.env
+}
+`,
+	})
+	planned, err := BuildDummyConfig("/", "/", nil, nil)
+	require.NoError(s.T(), err)
+	require.NoError(s.T(), planned.RunPlan())
+	require.NoError(s.T(), planned.(*DummyConfig).SavePlan("/plan.json"))
+
+	applied, err := BuildDummyConfig("/", "/", nil, nil)
+	require.NoError(s.T(), err)
+	require.NoError(s.T(), applied.(*DummyConfig).LoadPlan("/plan.json"))
+	s.Equal("prod", s.resource(applied, "resource.dummy.foo").Tags["env"])
+	child := Blocks[*ModuleBlock](applied)[0].ModuleConfig()
+	require.NotNil(s.T(), child)
+	s.Equal("prod", s.resource(child, "resource.dummy.bar").Tags["env"])
+	s.NoError(applied.RunApply())
+
+	require.NoError(s.T(), afero.WriteFile(s.fs, "/child/extra.hcl", []byte(`locals {}`), 0644))
+	changed, err := BuildDummyConfig("/", "/", nil, nil)
+	require.NoError(s.T(), err)
+	s.ErrorContains(changed.(*DummyConfig).LoadPlan("/plan.json"), "/child has changed since the plan was saved")
+}
+
+func (s *savedPlanSuite) TestSavedPlan_SensitiveValues() {
+	s.dummyFsWithFiles(map[string]string{
+		"/main.hcl": `locals {
+  password = sensitive("p@ssw0rd")
+}
+`,
+	})
+	planned, err := BuildDummyConfig("/", "/", nil, nil)
+	require.NoError(s.T(), err)
+	require.NoError(s.T(), planned.RunPlan())
+	require.NoError(s.T(), planned.(*DummyConfig).SavePlan("/.faketerraform/plan.json"))
+	content, err := afero.ReadFile(s.fs, "/.faketerraform/plan.json")
+	require.NoError(s.T(), err)
+	var saved SavedPlan
+	require.NoError(s.T(), json.Unmarshal(content, &saved))
+	s.True(saved.Blocks[0].Values["value"].HasMark(marks.Sensitive))
+
+	applied, err := BuildDummyConfig("/", "/", nil, nil)
+	require.NoError(s.T(), err)
+	require.NoError(s.T(), applied.(*DummyConfig).LoadPlan("/.faketerraform/plan.json"))
+	password := applied.EvalContext().Variables["local"].GetAttr("password")
+	s.True(password.HasMark(marks.Sensitive))
+	unmarked, _ := password.Unmark()
+	s.Equal(cty.StringVal("p@ssw0rd"), unmarked)
+}
+
+func (s *savedPlanSuite) TestSavedPlan_RefuseInconsistentOrder() {
+	s.dummyFsWithFiles(map[string]string{
+		"/main.hcl": savedPlanConfig,
+	})
+	planned := s.dummyConfig("prod")
+	require.NoError(s.T(), planned.RunPlan())
+	p, err := planned.savedPlan()
+	require.NoError(s.T(), err)
+	slices.Reverse(p.Order)
+	applied := s.dummyConfig("prod")
+	s.ErrorContains(applied.restorePlan(p), "saved plan order is inconsistent with the configuration")
+
+	p, err = planned.savedPlan()
+	require.NoError(s.T(), err)
+	p.Order = slices.DeleteFunc(p.Order, func(address string) bool {
+		return address == "resource.dummy.bar"
+	})
+	applied = s.dummyConfig("prod")
+	s.ErrorContains(applied.restorePlan(p), "resource.dummy.bar is not in the saved plan order")
+}
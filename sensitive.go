@@ -0,0 +1,107 @@
+package golden
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/lonegunmanb/hclfuncs/marks"
+	"github.com/zclconf/go-cty/cty"
+)
+
+// redactSensitive replaces marked parts of v, like values returned by `sensitive()`, with null, so v could be rendered or serialised.
+// It returns true if any part has been redacted.
+func redactSensitive(v cty.Value) (cty.Value, bool) {
+	if !v.ContainsMarked() {
+		return v, false
+	}
+	redacted, err := cty.Transform(v, func(_ cty.Path, v cty.Value) (cty.Value, error) {
+		if v.IsMarked() {
+			return cty.NullVal(v.Type()), nil
+		}
+		return v, nil
+	})
+	if err != nil {
+		// Transform fails only when the callback fails, unmark as the last resort so marks never leak into outputs.
+		redacted, _ = v.UnmarkDeep()
+		return cty.NullVal(redacted.Type()), true
+	}
+	return redacted, true
+}
+
+// pathStep is a step of cty.Path in JSON, `attr` for attributes or `key` for indexes of lists and maps.
+type pathStep struct {
+	Attr string          `json:"attr,omitempty"`
+	Key  json.RawMessage `json:"key,omitempty"`
+}
+
+// sensitivePaths unmarks v deeply and returns paths to the marked parts.
+// Paths that cannot be written in JSON, like elements of sets, are truncated to their closest collection.
+func sensitivePaths(v cty.Value) (cty.Value, [][]pathStep, error) {
+	unmarked, pvms := v.UnmarkDeepWithPaths()
+	var paths [][]pathStep
+	for _, pvm := range pvms {
+		steps := []pathStep{}
+		for _, step := range pvm.Path {
+			s, ok, err := newPathStep(step)
+			if err != nil {
+				return cty.NilVal, nil, err
+			}
+			if !ok {
+				break
+			}
+			steps = append(steps, s)
+		}
+		paths = append(paths, steps)
+	}
+	return unmarked, paths, nil
+}
+
+func newPathStep(step cty.PathStep) (pathStep, bool, error) {
+	switch s := step.(type) {
+	case cty.GetAttrStep:
+		return pathStep{Attr: s.Name}, true, nil
+	case cty.IndexStep:
+		if s.Key.Type() != cty.String && s.Key.Type() != cty.Number {
+			return pathStep{}, false, nil
+		}
+		key, err := json.Marshal(ctyJSONKey(s.Key))
+		return pathStep{Key: key}, err == nil, err
+	}
+	return pathStep{}, false, nil
+}
+
+func ctyJSONKey(key cty.Value) any {
+	if key.Type() == cty.String {
+		return key.AsString()
+	}
+	f, _ := key.AsBigFloat().Float64()
+	return f
+}
+
+// markSensitive marks parts of v at paths as sensitive, it's the reverse of sensitivePaths.
+func markSensitive(v cty.Value, paths [][]pathStep) (cty.Value, error) {
+	var pvms []cty.PathValueMarks
+	for _, steps := range paths {
+		var path cty.Path
+		for _, s := range steps {
+			if s.Key == nil {
+				path = path.GetAttr(s.Attr)
+				continue
+			}
+			var key any
+			if err := json.Unmarshal(s.Key, &key); err != nil {
+				return cty.NilVal, err
+			}
+			switch k := key.(type) {
+			case string:
+				path = path.Index(cty.StringVal(k))
+			case float64:
+				path = path.Index(cty.NumberFloatVal(k))
+			default:
+				return cty.NilVal, fmt.Errorf("unsupported key %s in sensitive path", string(s.Key))
+			}
+		}
+		pvms = append(pvms, cty.PathValueMarks{Path: path, Marks: cty.NewValueMarks(marks.Sensitive)})
+	}
+	return v.MarkWithPaths(pvms), nil
+}
@@ -0,0 +1,71 @@
+package golden
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/lonegunmanb/hclfuncs/marks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zclconf/go-cty/cty"
+)
+
+func TestRedactSensitive(t *testing.T) {
+	v := cty.ObjectVal(map[string]cty.Value{
+		"name":     cty.StringVal("a"),
+		"password": cty.StringVal("p@ssw0rd").Mark(marks.Sensitive),
+		"list":     cty.ListVal([]cty.Value{cty.StringVal("x"), cty.StringVal("y").Mark(marks.Sensitive)}),
+	})
+	redacted, ok := redactSensitive(v)
+	assert.True(t, ok)
+	assert.False(t, redacted.ContainsMarked())
+	assert.Equal(t, cty.ObjectVal(map[string]cty.Value{
+		"name":     cty.StringVal("a"),
+		"password": cty.NullVal(cty.String),
+		"list":     cty.ListVal([]cty.Value{cty.StringVal("x"), cty.NullVal(cty.String)}),
+	}), redacted)
+
+	redacted, ok = redactSensitive(cty.StringVal("a").Mark(marks.Sensitive))
+	assert.True(t, ok)
+	assert.Equal(t, cty.NullVal(cty.String), redacted)
+
+	redacted, ok = redactSensitive(cty.StringVal("a"))
+	assert.False(t, ok)
+	assert.Equal(t, cty.StringVal("a"), redacted)
+}
+
+func TestTypedValue_SensitiveRoundTrip(t *testing.T) {
+	cases := map[string]cty.Value{
+		"whole value": cty.StringVal("p@ssw0rd").Mark(marks.Sensitive),
+		"nested": cty.ObjectVal(map[string]cty.Value{
+			"name": cty.StringVal("a"),
+			"tags": cty.MapVal(map[string]cty.Value{
+				"secret": cty.StringVal("s").Mark(marks.Sensitive),
+				"public": cty.StringVal("p"),
+			}),
+			"list": cty.ListVal([]cty.Value{cty.StringVal("x"), cty.StringVal("y").Mark(marks.Sensitive)}),
+		}),
+	}
+	for n, v := range cases {
+		t.Run(n, func(t *testing.T) {
+			content, err := json.Marshal(TypedValue{v})
+			require.NoError(t, err)
+			assert.Contains(t, string(content), `"sensitive_paths"`)
+			var restored TypedValue
+			require.NoError(t, json.Unmarshal(content, &restored))
+			assert.True(t, v.RawEquals(restored.Value), restored.GoString())
+		})
+	}
+}
+
+func TestTypedValue_SetElementMarksWholeSet(t *testing.T) {
+	v := cty.ObjectVal(map[string]cty.Value{
+		"set": cty.SetVal([]cty.Value{cty.StringVal("x").Mark(marks.Sensitive)}),
+	})
+	content, err := json.Marshal(TypedValue{v})
+	require.NoError(t, err)
+	var restored TypedValue
+	require.NoError(t, json.Unmarshal(content, &restored))
+	assert.True(t, restored.GetAttr("set").HasMark(marks.Sensitive))
+	assert.False(t, restored.HasMark(marks.Sensitive))
+}
@@ -0,0 +1,367 @@
+package golden
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/go-multierror"
+	"github.com/spf13/afero"
+	"github.com/zclconf/go-cty/cty"
+)
+
+// StateFormatVersion is the version of state files, files with other versions are refused.
+const StateFormatVersion = "1.0"
+
+type StateAction string
+
+const (
+	StateActionCreate StateAction = "create"
+	StateActionUpdate StateAction = "update"
+	StateActionDelete StateAction = "delete"
+	StateActionNoOp   StateAction = "no-op"
+	// StateActionReplace destroys and applies the block again, it's triggered by `lifecycle.replace_triggered_by`.
+	StateActionReplace StateAction = "replace"
+)
+
+// State is the content of a local state file, it records attribute values of every applied ApplyBlock by full address,
+// blocks of modules are recorded like `module.child.resource.dummy.bar`.
+type State struct {
+	FormatVersion string `json:"format_version"`
+	// Serial increases every time the state is written, it detects concurrent runs between plan and apply.
+	Serial    int                   `json:"serial"`
+	Workspace string                `json:"workspace"`
+	Blocks    map[string]StateBlock `json:"blocks"`
+}
+
+type StateBlock struct {
+	BlockType  string                `json:"block_type"`
+	Type       string                `json:"type,omitempty"`
+	Labels     []string              `json:"labels"`
+	ForEachKey *string               `json:"for_each_key,omitempty"`
+	Values     map[string]TypedValue `json:"values"`
+	// DependsOn are addresses of ApplyBlocks this block depends on, blocks are destroyed before their dependencies.
+	DependsOn []string `json:"depends_on,omitempty"`
+	// PreventDestroy and CreateBeforeDestroy are kept from `lifecycle`, so they still work after the block is removed from configuration.
+	PreventDestroy      bool `json:"prevent_destroy,omitempty"`
+	CreateBeforeDestroy bool `json:"create_before_destroy,omitempty"`
+}
+
+// StateChange is the action apply would take on an address compared with prior state.
+type StateChange struct {
+	Address string      `json:"address"`
+	Action  StateAction `json:"action"`
+}
+
+func newState(workspace string) *State {
+	return &State{
+		FormatVersion: StateFormatVersion,
+		Workspace:     workspace,
+		Blocks:        make(map[string]StateBlock),
+	}
+}
+
+// PriorState returns values of b recorded by the last apply, it's false if state is disabled or b has never been applied.
+func PriorState(b Block) (map[string]cty.Value, bool) {
+	c, ok := b.Config().(interface{ baseConfig() *BaseConfig })
+	if !ok || c.baseConfig().priorState == nil {
+		return nil, false
+	}
+	sb, ok := c.baseConfig().priorState.Blocks[FullAddress(b)]
+	if !ok {
+		return nil, false
+	}
+	values := make(map[string]cty.Value)
+	for n, v := range sb.Values {
+		values[n] = v.Value
+	}
+	return values, true
+}
+
+// StateChanges compares planned ApplyBlocks with prior state, it should be called after RunPlan and returns nil if state is disabled.
+func (c *BaseConfig) StateChanges() []StateChange {
+	if c.priorState == nil {
+		return nil
+	}
+	var changes []StateChange
+	planned := make(map[string]struct{})
+	actions := make(map[string]StateAction)
+	for _, b := range applyBlocksWithModules(c) {
+		address := FullAddress(b)
+		planned[address] = struct{}{}
+		action := StateActionCreate
+		if prior, ok := PriorState(b); ok {
+			action = StateActionUpdate
+			if stateValuesEqual(prior, stateValues(b)) {
+				action = StateActionNoOp
+			}
+		}
+		actions[address] = action
+	}
+	// a replacement could trigger other replacements, so repeat until nothing changes.
+	// `replace_triggered_by` only applies to blocks of the root config.
+	applyBlocks := Blocks[ApplyBlock](c)
+	for triggered := true; triggered; {
+		triggered = false
+		for _, b := range applyBlocks {
+			lc, err := blockLifecycle(b)
+			action := actions[b.Address()]
+			if err != nil || action == StateActionCreate || action == StateActionReplace || !replaceTriggered(lc, actions) {
+				continue
+			}
+			actions[b.Address()] = StateActionReplace
+			triggered = true
+		}
+	}
+	for address, action := range actions {
+		changes = append(changes, StateChange{Address: address, Action: action})
+	}
+	for address := range c.priorState.Blocks {
+		if _, ok := planned[address]; !ok {
+			changes = append(changes, StateChange{Address: address, Action: StateActionDelete})
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].Address < changes[j].Address
+	})
+	return changes
+}
+
+// applyBlocksWithModules returns ApplyBlocks of c and of child configs of its modules.
+func applyBlocksWithModules(c *BaseConfig) []ApplyBlock {
+	r := Blocks[ApplyBlock](c)
+	for _, m := range Blocks[*ModuleBlock](c) {
+		if m.child != nil {
+			r = append(r, applyBlocksWithModules(m.child)...)
+		}
+	}
+	return r
+}
+
+// configured returns true if a block address recorded in state is still in configuration,
+// blocks of a module that has not been loaded are regarded as configured so they're never destroyed by mistake.
+func (c *BaseConfig) configured(address string) bool {
+	if c.d.exist(address) {
+		return true
+	}
+	for _, m := range Blocks[*ModuleBlock](c) {
+		if rest, ok := strings.CutPrefix(address, m.Address()+"."); ok {
+			return m.child == nil || m.child.configured(rest)
+		}
+	}
+	return false
+}
+
+func stateValues(b Block) map[string]cty.Value {
+	r := make(map[string]cty.Value)
+	for n, v := range Value(b) {
+		if v != cty.NilVal {
+			r[n] = v
+		}
+	}
+	return r
+}
+
+func stateValuesEqual(a, b map[string]cty.Value) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for n, v := range a {
+		if bv, ok := b[n]; !ok || !v.RawEquals(bv) {
+			return false
+		}
+	}
+	return true
+}
+
+// loadPriorState reads state under lock before plan, so blocks could compare with values of the last apply.
+func (c *BaseConfig) loadPriorState() error {
+	if c.statePath == "" {
+		return nil
+	}
+	unlock, err := c.lockState()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	s, err := c.readState()
+	if err != nil {
+		return err
+	}
+	c.priorState = s
+	return nil
+}
+
+// applyWithState runs apply under lock and records values of applied blocks, the state must not change since plan.
+func (c *BaseConfig) applyWithState() error {
+	unlock, err := c.lockState()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	s, err := c.readState()
+	if err != nil {
+		return err
+	}
+	if c.priorState == nil || s.Serial != c.priorState.Serial {
+		return fmt.Errorf("state %s has been changed by another run since plan, please plan again", c.statePath)
+	}
+	replaces := make(map[string]struct{})
+	for _, change := range c.StateChanges() {
+		if change.Action == StateActionReplace {
+			replaces[change.Address] = struct{}{}
+		}
+	}
+	applyErr := c.destroyRemovedBlocks(s, false)
+	err = c.runDag(func(b Block) error {
+		_, replace := replaces[b.Address()]
+		lc, err := blockLifecycle(b)
+		if err != nil {
+			return err
+		}
+		if replace && !lc.CreateBeforeDestroy {
+			if err = c.destroyForReplace(b, lc, s); err != nil {
+				return err
+			}
+		}
+		if err = dagApply(b); err != nil {
+			return err
+		}
+		if replace && lc.CreateBeforeDestroy {
+			if err = c.destroyForReplace(b, lc, s); err != nil {
+				return err
+			}
+		}
+		return recordState(s, b)
+	})
+	if err != nil {
+		applyErr = multierror.Append(applyErr, err)
+	}
+	if err = c.destroyRemovedBlocks(s, true); err != nil {
+		applyErr = multierror.Append(applyErr, err)
+	}
+	s.Serial++
+	if err = c.writeState(s); err != nil {
+		applyErr = multierror.Append(applyErr, err)
+	}
+	c.priorState = s
+	return applyErr
+}
+
+// recordState records b in s by its full address, blocks of the child config are recorded along with a module.
+func recordState(s *State, b Block) error {
+	if _, ok := b.(ApplyBlock); !ok {
+		return nil
+	}
+	sb, err := stateBlock(b)
+	if err != nil {
+		return err
+	}
+	s.Blocks[FullAddress(b)] = sb
+	m, ok := b.(*ModuleBlock)
+	if !ok || m.child == nil {
+		return nil
+	}
+	for _, cb := range blocks(m.child) {
+		if err = recordState(s, cb); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func stateBlock(b Block) (StateBlock, error) {
+	sb := StateBlock{
+		BlockType: b.BlockType(),
+		Type:      b.Type(),
+		Labels:    b.HclBlock().Labels,
+		Values:    make(map[string]TypedValue),
+	}
+	lc, err := blockLifecycle(b)
+	if err != nil {
+		return sb, err
+	}
+	sb.PreventDestroy = lc.PreventDestroy
+	sb.CreateBeforeDestroy = lc.CreateBeforeDestroy
+	if fe := b.getForEach(); fe != nil {
+		key := CtyValueToString(fe.key)
+		sb.ForEachKey = &key
+	}
+	for n, v := range stateValues(b) {
+		sb.Values[n] = TypedValue{v}
+	}
+	ancestors, err := b.Config().GetAncestors(b.Address())
+	if err != nil {
+		return sb, err
+	}
+	for address, a := range ancestors {
+		if _, ok := a.(ApplyBlock); ok {
+			sb.DependsOn = append(sb.DependsOn, moduleAddress(b.Config(), address))
+		}
+	}
+	sort.Strings(sb.DependsOn)
+	return sb, nil
+}
+
+func (c *BaseConfig) readState() (*State, error) {
+	content, err := afero.ReadFile(configFs, c.statePath)
+	if errors.Is(err, os.ErrNotExist) {
+		return newState(c.Workspace()), nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("cannot read state %s: %+v", c.statePath, err)
+	}
+	s := newState(c.Workspace())
+	if err = json.Unmarshal(content, s); err != nil {
+		return nil, fmt.Errorf("cannot parse state %s: %+v", c.statePath, err)
+	}
+	if s.FormatVersion != StateFormatVersion {
+		return nil, fmt.Errorf("unsupported state format version %s in %s, want %s", s.FormatVersion, c.statePath, StateFormatVersion)
+	}
+	if s.Blocks == nil {
+		s.Blocks = make(map[string]StateBlock)
+	}
+	return s, nil
+}
+
+// writeState writes a temp file then renames it, so a crash never leaves a partial state file.
+func (c *BaseConfig) writeState(s *State) error {
+	content, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return fmt.Errorf("cannot marshal state: %+v", err)
+	}
+	if err = configFs.MkdirAll(filepath.Dir(c.statePath), 0755); err != nil {
+		return err
+	}
+	tmp := c.statePath + ".tmp"
+	if err = afero.WriteFile(configFs, tmp, content, 0600); err != nil {
+		return fmt.Errorf("cannot write state %s: %+v", c.statePath, err)
+	}
+	return configFs.Rename(tmp, c.statePath)
+}
+
+// lockState creates `<state>.lock` exclusively, it fails if another run holds the lock.
+func (c *BaseConfig) lockState() (func(), error) {
+	lockPath := c.statePath + ".lock"
+	if err := configFs.MkdirAll(filepath.Dir(lockPath), 0755); err != nil {
+		return nil, err
+	}
+	f, err := configFs.OpenFile(lockPath, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0600)
+	if err != nil {
+		if errors.Is(err, os.ErrExist) {
+			info, _ := afero.ReadFile(configFs, lockPath)
+			return nil, fmt.Errorf("state %s is locked by another run (%s), remove %s if no other run is in progress", c.statePath, string(info), lockPath)
+		}
+		return nil, fmt.Errorf("cannot lock state %s: %+v", c.statePath, err)
+	}
+	_, _ = fmt.Fprintf(f, "pid %d since %s", os.Getpid(), time.Now().UTC().Format(time.RFC3339))
+	_ = f.Close()
+	return func() {
+		_ = configFs.Remove(lockPath)
+	}, nil
+}
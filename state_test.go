@@ -0,0 +1,147 @@
+package golden
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"github.com/zclconf/go-cty/cty"
+)
+
+type stateSuite struct {
+	suite.Suite
+	*testBase
+}
+
+func TestStateSuite(t *testing.T) {
+	suite.Run(t, new(stateSuite))
+}
+
+func (s *stateSuite) SetupTest() {
+	s.testBase = newTestBase()
+}
+
+func (s *stateSuite) TearDownTest() {
+	s.teardown()
+}
+
+const testStatePath = "/.faketerraform/workspaces/default/state.json"
+
+func (s *stateSuite) statefulConfig() *DummyConfig {
+	hclBlocks, err := loadHclBlocks(false, "/")
+	require.NoError(s.T(), err)
+	c := &DummyConfig{
+		BaseConfig: NewBasicConfigFromArgs(NewBaseConfigArgs{
+			Basedir:         "/",
+			DslFullName:     "faketerraform",
+			DslAbbreviation: "ft",
+			StatePath:       testStatePath,
+		}),
+	}
+	require.NoError(s.T(), InitConfig(c, hclBlocks))
+	return c
+}
+
+func (s *stateSuite) writeConfig(config string) {
+	require.NoError(s.T(), afero.WriteFile(s.fs, "/main.hcl", []byte(config), 0644))
+}
+
+func (s *stateSuite) TestState_ChangesAcrossRuns() {
+	s.writeConfig(`resource "dummy" "foo" {
+  tags = {
+    env = "dev"
+  }
+}
+
+resource "dummy" "bar" {
+}
+
+data "dummy" "baz" {
+}
+`)
+	first := s.statefulConfig()
+	require.NoError(s.T(), first.RunPlan())
+	s.Equal([]StateChange{
+		{Address: "resource.dummy.bar", Action: StateActionCreate},
+		{Address: "resource.dummy.foo", Action: StateActionCreate},
+	}, first.StateChanges())
+	require.NoError(s.T(), first.RunApply())
+
+	content, err := afero.ReadFile(s.fs, testStatePath)
+	require.NoError(s.T(), err)
+	var state State
+	require.NoError(s.T(), json.Unmarshal(content, &state))
+	s.Equal(1, state.Serial)
+	s.Equal("resource", state.Blocks["resource.dummy.foo"].BlockType)
+	s.Equal(cty.MapVal(map[string]cty.Value{"env": cty.StringVal("dev")}), state.Blocks["resource.dummy.foo"].Values["tags"].Value)
+	s.NotContains(state.Blocks, "data.dummy.baz")
+
+	second := s.statefulConfig()
+	require.NoError(s.T(), second.RunPlan())
+	s.Equal([]StateChange{
+		{Address: "resource.dummy.bar", Action: StateActionNoOp},
+		{Address: "resource.dummy.foo", Action: StateActionNoOp},
+	}, second.StateChanges())
+	prior, ok := PriorState(Blocks[*DummyResource](second)[0])
+	s.True(ok)
+	s.Contains(prior, "tags")
+
+	s.writeConfig(`resource "dummy" "foo" {
+  tags = {
+    env = "prod"
+  }
+}
+`)
+	third := s.statefulConfig()
+	require.NoError(s.T(), third.RunPlan())
+	s.Equal([]StateChange{
+		{Address: "resource.dummy.bar", Action: StateActionDelete},
+		{Address: "resource.dummy.foo", Action: StateActionUpdate},
+	}, third.StateChanges())
+}
+
+func (s *stateSuite) TestState_Lock() {
+	s.writeConfig(`resource "dummy" "foo" {
+}
+`)
+	c := s.statefulConfig()
+	unlock, err := c.lockState()
+	require.NoError(s.T(), err)
+	err = c.RunPlan()
+	s.ErrorContains(err, "is locked by another run")
+	unlock()
+	s.NoError(c.RunPlan())
+	exist, err := afero.Exists(s.fs, testStatePath+".lock")
+	require.NoError(s.T(), err)
+	s.False(exist)
+}
+
+func (s *stateSuite) TestState_RefuseApplyAfterAnotherRun() {
+	s.writeConfig(`resource "dummy" "foo" {
+}
+`)
+	a := s.statefulConfig()
+	require.NoError(s.T(), a.RunPlan())
+	require.NoError(s.T(), a.SavePlan("/plan.json"))
+	b := s.statefulConfig()
+	require.NoError(s.T(), b.RunPlan())
+	require.NoError(s.T(), b.RunApply())
+
+	s.ErrorContains(a.RunApply(), "has been changed by another run since plan")
+	c := s.statefulConfig()
+	s.ErrorContains(c.LoadPlan("/plan.json"), "has been changed by another run since the plan was saved")
+}
+
+func (s *stateSuite) TestState_DisabledByDefault() {
+	s.writeConfig(`resource "dummy" "foo" {
+}
+`)
+	c, err := BuildDummyConfig("/", "/", nil, nil)
+	require.NoError(s.T(), err)
+	require.NoError(s.T(), c.RunPlan())
+	s.Nil(c.(*DummyConfig).StateChanges())
+	_, ok := PriorState(Blocks[*DummyResource](c)[0])
+	s.False(ok)
+}
@@ -0,0 +1,132 @@
+package golden
+
+import (
+	"context"
+	"time"
+)
+
+// Metric names recorded through Meter, durations are in seconds.
+const (
+	MetricDecodeDuration     = "golden.decode.duration"
+	MetricExpressionDuration = "golden.expression.duration"
+	MetricBlockFailures      = "golden.block.failures"
+)
+
+// Tracer, Span and Meter follow the shape of OpenTelemetry API, so DSLs can adapt an otel tracer and meter without golden depending on otel.
+type Tracer interface {
+	Start(ctx context.Context, name string, attrs ...Attribute) (context.Context, Span)
+}
+
+type Span interface {
+	SetAttributes(attrs ...Attribute)
+	RecordError(err error)
+	End()
+}
+
+type Meter interface {
+	Int64Counter(name string) Int64Counter
+	Float64Histogram(name string) Float64Histogram
+}
+
+type Int64Counter interface {
+	Add(ctx context.Context, incr int64, attrs ...Attribute)
+}
+
+type Float64Histogram interface {
+	Record(ctx context.Context, value float64, attrs ...Attribute)
+}
+
+type Attribute struct {
+	Key   string
+	Value any
+}
+
+func Attr(key string, value any) Attribute {
+	return Attribute{Key: key, Value: value}
+}
+
+type nopTracer struct{}
+
+func (nopTracer) Start(ctx context.Context, _ string, _ ...Attribute) (context.Context, Span) {
+	return ctx, nopSpan{}
+}
+
+type nopSpan struct{}
+
+func (nopSpan) SetAttributes(...Attribute) {}
+func (nopSpan) RecordError(error)          {}
+func (nopSpan) End()                       {}
+
+type nopMeter struct{}
+
+func (nopMeter) Int64Counter(string) Int64Counter         { return nopInstrument{} }
+func (nopMeter) Float64Histogram(string) Float64Histogram { return nopInstrument{} }
+
+type nopInstrument struct{}
+
+func (nopInstrument) Add(context.Context, int64, ...Attribute)      {}
+func (nopInstrument) Record(context.Context, float64, ...Attribute) {}
+
+// traceRun wraps a DAG run with a `golden.<phase>` span, spans of blocks executed during the run are nested under it.
+func (c *BaseConfig) traceRun(phase string, run func() error) error {
+	parent := c.spanCtx
+	if parent == nil {
+		parent = c.ctx
+	}
+	ctx, span := c.tracer.Start(parent, "golden."+phase, Attr("golden.dsl", c.dslFullName), Attr("golden.workspace", c.Workspace()))
+	previousCtx, previousPhase := c.spanCtx, c.phase
+	c.spanCtx, c.phase = ctx, phase
+	defer func() {
+		c.spanCtx, c.phase = previousCtx, previousPhase
+	}()
+	err := run()
+	if err != nil {
+		span.RecordError(err)
+	}
+	span.End()
+	return err
+}
+
+func (c *BaseConfig) traceBlock(b Block, onReady func(Block) error) error {
+	if c.phase == "" {
+		return onReady(b)
+	}
+	parent := c.spanCtx
+	ctx, span := c.tracer.Start(parent, FullAddress(b), c.blockAttributes(b)...)
+	c.spanCtx = ctx
+	defer func() {
+		c.spanCtx = parent
+	}()
+	err := onReady(b)
+	if err != nil {
+		span.RecordError(err)
+		c.meter.Int64Counter(MetricBlockFailures).Add(ctx, 1, c.blockAttributes(b)...)
+	}
+	span.End()
+	return err
+}
+
+func (c *BaseConfig) blockAttributes(b Block) []Attribute {
+	return []Attribute{
+		Attr("golden.phase", c.phase),
+		Attr("golden.block.address", FullAddress(b)),
+		Attr("golden.block.type", b.BlockType()),
+	}
+}
+
+// recordDuration records seconds elapsed since start to the histogram of the block's config.
+func recordDuration(b Block, name string, start time.Time, attrs ...Attribute) {
+	bc, ok := b.Config().(interface{ baseConfig() *BaseConfig })
+	if !ok {
+		return
+	}
+	c := bc.baseConfig()
+	if c.meter == nil {
+		return
+	}
+	ctx := c.spanCtx
+	if ctx == nil {
+		ctx = c.ctx
+	}
+	c.meter.Float64Histogram(name).Record(ctx, time.Since(start).Seconds(), append(c.blockAttributes(b), attrs...)...)
+}
@@ -0,0 +1,151 @@
+package golden
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+var _ Tracer = &InMemoryTracer{}
+var _ Meter = &InMemoryMeter{}
+
+// InMemoryTracer keeps spans in memory so DSLs can assert on them in tests without a collector.
+type InMemoryTracer struct {
+	mu    sync.Mutex
+	spans []*RecordedSpan
+}
+
+type RecordedSpan struct {
+	Name       string
+	Parent     *RecordedSpan
+	Attributes map[string]any
+	Errors     []error
+	StartTime  time.Time
+	// EndTime is zero until the span is ended.
+	EndTime time.Time
+	mu      *sync.Mutex
+}
+
+type recordedSpanKey struct{}
+
+func (t *InMemoryTracer) Start(ctx context.Context, name string, attrs ...Attribute) (context.Context, Span) {
+	parent, _ := ctx.Value(recordedSpanKey{}).(*RecordedSpan)
+	span := &RecordedSpan{
+		Name:       name,
+		Parent:     parent,
+		Attributes: make(map[string]any),
+		StartTime:  time.Now(),
+		mu:         &t.mu,
+	}
+	span.SetAttributes(attrs...)
+	t.mu.Lock()
+	t.spans = append(t.spans, span)
+	t.mu.Unlock()
+	return context.WithValue(ctx, recordedSpanKey{}, span), span
+}
+
+// Spans returns all started spans in order.
+func (t *InMemoryTracer) Spans() []*RecordedSpan {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return append([]*RecordedSpan(nil), t.spans...)
+}
+
+// Span returns the first span with the name, or nil.
+func (t *InMemoryTracer) Span(name string) *RecordedSpan {
+	for _, s := range t.Spans() {
+		if s.Name == name {
+			return s
+		}
+	}
+	return nil
+}
+
+func (s *RecordedSpan) SetAttributes(attrs ...Attribute) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, a := range attrs {
+		s.Attributes[a.Key] = a.Value
+	}
+}
+
+func (s *RecordedSpan) RecordError(err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.Errors = append(s.Errors, err)
+}
+
+func (s *RecordedSpan) End() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.EndTime = time.Now()
+}
+
+// InMemoryMeter keeps measurements in memory so DSLs can assert on them in tests without a collector.
+type InMemoryMeter struct {
+	mu           sync.Mutex
+	measurements []Measurement
+}
+
+type Measurement struct {
+	Name       string
+	Value      float64
+	Attributes map[string]any
+}
+
+type inMemoryInstrument struct {
+	name  string
+	meter *InMemoryMeter
+}
+
+func (m *InMemoryMeter) Int64Counter(name string) Int64Counter {
+	return inMemoryInstrument{name: name, meter: m}
+}
+
+func (m *InMemoryMeter) Float64Histogram(name string) Float64Histogram {
+	return inMemoryInstrument{name: name, meter: m}
+}
+
+// Measurements returns recorded values of the instrument in order.
+func (m *InMemoryMeter) Measurements(name string) []Measurement {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var r []Measurement
+	for _, ms := range m.measurements {
+		if ms.Name == name {
+			r = append(r, ms)
+		}
+	}
+	return r
+}
+
+// Sum returns the sum of recorded values of the instrument, it's the value of a counter.
+func (m *InMemoryMeter) Sum(name string) float64 {
+	var sum float64
+	for _, ms := range m.Measurements(name) {
+		sum += ms.Value
+	}
+	return sum
+}
+
+func (i inMemoryInstrument) Add(_ context.Context, incr int64, attrs ...Attribute) {
+	i.meter.record(i.name, float64(incr), attrs)
+}
+
+func (i inMemoryInstrument) Record(_ context.Context, value float64, attrs ...Attribute) {
+	i.meter.record(i.name, value, attrs)
+}
+
+func (m *InMemoryMeter) record(name string, value float64, attrs []Attribute) {
+	ms := Measurement{
+		Name:       name,
+		Value:      value,
+		Attributes: make(map[string]any),
+	}
+	for _, a := range attrs {
+		ms.Attributes[a.Key] = a.Value
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.measurements = append(m.measurements, ms)
+}
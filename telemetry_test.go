@@ -0,0 +1,145 @@
+package golden
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type telemetrySuite struct {
+	suite.Suite
+	*testBase
+	tracer *InMemoryTracer
+	meter  *InMemoryMeter
+}
+
+func TestTelemetrySuite(t *testing.T) {
+	suite.Run(t, new(telemetrySuite))
+}
+
+func (s *telemetrySuite) SetupTest() {
+	s.testBase = newTestBase()
+	s.tracer = &InMemoryTracer{}
+	s.meter = &InMemoryMeter{}
+}
+
+func (s *telemetrySuite) TearDownTest() {
+	s.teardown()
+}
+
+func (s *telemetrySuite) config(files map[string]string) *DummyConfig {
+	s.dummyFsWithFiles(files)
+	hclBlocks, err := loadHclBlocks(false, "/")
+	require.NoError(s.T(), err)
+	c := &DummyConfig{
+		BaseConfig: NewBasicConfigFromArgs(NewBaseConfigArgs{
+			Basedir:         "/",
+			DslFullName:     "faketerraform",
+			DslAbbreviation: "ft",
+			Ctx:             context.Background(),
+			Tracer:          s.tracer,
+			Meter:           s.meter,
+		}),
+	}
+	require.NoError(s.T(), InitConfig(c, hclBlocks))
+	return c
+}
+
+func (s *telemetrySuite) TestTelemetry_BlockSpansNestedUnderRunSpan() {
+	c := s.config(map[string]string{
+		"/main.hcl": `resource "dummy" "foo" {
+  for_each = toset(["a"])
+  tags = {
+    key = each.value
+  }
+}
+`,
+	})
+	require.NoError(s.T(), c.RunPlan())
+	require.NoError(s.T(), c.RunApply())
+
+	prePlan := s.tracer.Span("golden.pre_plan")
+	require.NotNil(s.T(), prePlan)
+	s.Nil(prePlan.Parent)
+	s.Equal("faketerraform", prePlan.Attributes["golden.dsl"])
+	s.Equal("default", prePlan.Attributes["golden.workspace"])
+
+	var blockSpans []*RecordedSpan
+	for _, span := range s.tracer.Spans() {
+		s.False(span.EndTime.IsZero(), span.Name)
+		if span.Name == "resource.dummy.foo[a]" {
+			blockSpans = append(blockSpans, span)
+		}
+	}
+	require.Len(s.T(), blockSpans, 3)
+	for i, phase := range []string{"pre_plan", "plan", "apply"} {
+		s.Equal("golden."+phase, blockSpans[i].Parent.Name)
+		s.Equal(phase, blockSpans[i].Attributes["golden.phase"])
+		s.Equal("resource", blockSpans[i].Attributes["golden.block.type"])
+	}
+
+	decodes := s.meter.Measurements(MetricDecodeDuration)
+	require.Len(s.T(), decodes, 1)
+	s.Equal("resource.dummy.foo[a]", decodes[0].Attributes["golden.block.address"])
+	s.GreaterOrEqual(decodes[0].Value, float64(0))
+	var kinds []any
+	for _, m := range s.meter.Measurements(MetricExpressionDuration) {
+		kinds = append(kinds, m.Attributes["golden.expression.kind"])
+	}
+	s.Equal([]any{"for_each", "precondition", "postcondition"}, kinds)
+	s.Zero(s.meter.Sum(MetricBlockFailures))
+}
+
+func (s *telemetrySuite) TestTelemetry_FailuresAreRecorded() {
+	c := s.config(map[string]string{
+		"/main.hcl": `data "dummy" "foo" {
+  precondition {
+    condition     = false
+    error_message = "foo is not ready"
+  }
+}
+`,
+	})
+	s.NotNil(c.RunPlan())
+	s.Equal(float64(1), s.meter.Sum(MetricBlockFailures))
+	failure := s.meter.Measurements(MetricBlockFailures)[0]
+	s.Equal("plan", failure.Attributes["golden.phase"])
+	s.Equal("data.dummy.foo", failure.Attributes["golden.block.address"])
+	var errs []error
+	for _, span := range s.tracer.Spans() {
+		if span.Name == "data.dummy.foo" {
+			errs = append(errs, span.Errors...)
+		}
+	}
+	require.Len(s.T(), errs, 1)
+	s.ErrorContains(errs[0], "foo is not ready")
+	s.Len(s.tracer.Span("golden.plan").Errors, 1)
+}
+
+func (s *telemetrySuite) TestTelemetry_ModuleRunNestedUnderModuleBlockSpan() {
+	c := s.config(map[string]string{
+		"/main.hcl": `module "greeting" {
+  source = "./greeting"
+  name   = "world"
+}
+`,
+		"/greeting/main.hcl": greetingModule,
+	})
+	require.NoError(s.T(), c.RunPlan())
+	require.NoError(s.T(), c.RunApply())
+
+	var childRuns []*RecordedSpan
+	for _, span := range s.tracer.Spans() {
+		if span.Name == "golden.plan" && span.Parent != nil || span.Name == "golden.apply" && span.Parent != nil {
+			childRuns = append(childRuns, span)
+		}
+	}
+	require.Len(s.T(), childRuns, 2)
+	for i, phase := range []string{"plan", "apply"} {
+		s.Equal("module.greeting", childRuns[i].Parent.Name)
+		s.Equal(phase, childRuns[i].Parent.Attributes["golden.phase"])
+		s.Equal("golden."+phase, childRuns[i].Parent.Parent.Name)
+	}
+}
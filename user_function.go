@@ -0,0 +1,257 @@
+package golden
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/ext/typeexpr"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/lonegunmanb/hclfuncs"
+	"github.com/zclconf/go-cty/cty"
+	"github.com/zclconf/go-cty/cty/convert"
+	"github.com/zclconf/go-cty/cty/function"
+)
+
+var _ PrePlanBlock = &FunctionBlock{}
+var _ CustomDecode = &FunctionBlock{}
+
+// FunctionBlock is a pure function declared in config:
+//
+//	function "add" {
+//	  params      = [a, b]
+//	  param_types = [number, number]
+//	  result_type = number
+//	  result      = a + b
+//	}
+//
+// `param_types` and `result_type` are optional and default to `any`, `result` can only reference params.
+type FunctionBlock struct {
+	*BaseBlock
+	Params     []string
+	ParamTypes []cty.Type
+	ResultType cty.Type
+	result     hclsyntax.Expression
+	compiled   *function.Function
+	compileErr error
+}
+
+func (f *FunctionBlock) Decode(*HclBlock, *hcl.EvalContext) error {
+	return nil
+}
+
+// ExecuteBeforePlan reports compile errors and recursive calls, so misuse is surfaced by InitConfig.
+func (f *FunctionBlock) ExecuteBeforePlan() error {
+	_, err := f.function()
+	return err
+}
+
+func (f *FunctionBlock) function() (function.Function, error) {
+	if f.compiled == nil {
+		fn, err := f.compile()
+		if err == nil {
+			err = f.checkRecursion()
+		}
+		f.compiled, f.compileErr = &fn, err
+	}
+	return *f.compiled, f.compileErr
+}
+
+func (f *FunctionBlock) compile() (function.Function, error) {
+	var diags hcl.Diagnostics
+	attrs := f.HclBlock().Body.Attributes
+	subject := f.HclBlock().Range().Ptr()
+	if _, conflict := hclfuncs.Functions("")[f.Name()]; conflict {
+		return function.Function{}, hcl.Diagnostics{f.diagnostic("Function name conflicts with built-in function", fmt.Sprintf("function.%s conflicts with built-in function `%s`", f.Name(), f.Name()), subject)}
+	}
+	if bc, ok := f.c.(interface{ baseConfig() *BaseConfig }); ok {
+		// functions supplied by the DSL must not be shadowed by config silently.
+		_, overridden := bc.baseConfig().OverrideFunctions[f.Name()]
+		_, registered := bc.baseConfig().registeredFunctions[f.Name()]
+		if overridden || registered {
+			return function.Function{}, hcl.Diagnostics{f.diagnostic("Function name conflicts with DSL function", fmt.Sprintf("function.%s conflicts with function `%s` provided by %s", f.Name(), f.Name(), bc.baseConfig().DslFullName()), subject)}
+		}
+	}
+	f.Params = nil
+	if paramsAttr, ok := attrs["params"]; ok {
+		exprs, diag := hcl.ExprList(paramsAttr.Expr)
+		diags = diags.Extend(diag)
+		for _, expr := range exprs {
+			name := hcl.ExprAsKeyword(expr)
+			if name == "" {
+				diags = diags.Append(f.diagnostic("Invalid function param", "params must be a list of bare identifiers, like `params = [a, b]`", expr.Range().Ptr()))
+				continue
+			}
+			for _, p := range f.Params {
+				if p == name {
+					diags = diags.Append(f.diagnostic("Duplicate function param", fmt.Sprintf("param `%s` of function.%s is declared more than once", name, f.Name()), expr.Range().Ptr()))
+				}
+			}
+			f.Params = append(f.Params, name)
+		}
+	}
+	f.ParamTypes = make([]cty.Type, len(f.Params))
+	for i := range f.ParamTypes {
+		f.ParamTypes[i] = cty.DynamicPseudoType
+	}
+	if typesAttr, ok := attrs["param_types"]; ok {
+		exprs, diag := hcl.ExprList(typesAttr.Expr)
+		diags = diags.Extend(diag)
+		if !diag.HasErrors() && len(exprs) != len(f.Params) {
+			diags = diags.Append(f.diagnostic("Invalid param_types", fmt.Sprintf("function.%s declares %d params but %d param_types", f.Name(), len(f.Params), len(exprs)), typesAttr.SrcRange.Ptr()))
+		}
+		for i, expr := range exprs {
+			t, diag := typeexpr.TypeConstraint(expr)
+			diags = diags.Extend(diag)
+			if i < len(f.ParamTypes) {
+				f.ParamTypes[i] = t
+			}
+		}
+	}
+	f.ResultType = cty.DynamicPseudoType
+	if typeAttr, ok := attrs["result_type"]; ok {
+		t, diag := typeexpr.TypeConstraint(typeAttr.Expr)
+		diags = diags.Extend(diag)
+		f.ResultType = t
+	}
+	resultAttr, ok := attrs["result"]
+	if !ok {
+		diags = diags.Append(f.diagnostic("Missing function result", fmt.Sprintf("function.%s requires `result` attribute", f.Name()), subject))
+	} else {
+		f.result = resultAttr.Expr
+		for _, t := range resultAttr.Expr.Variables() {
+			if !f.isParam(t.RootName()) {
+				diags = diags.Append(f.diagnostic("Invalid reference in function", fmt.Sprintf("function.%s can only reference its params, `%s` is not allowed", f.Name(), traversalString(t)), t.SourceRange().Ptr()))
+			}
+		}
+	}
+	if diags.HasErrors() {
+		return function.Function{}, diags
+	}
+	var params []function.Parameter
+	for i, n := range f.Params {
+		params = append(params, function.Parameter{
+			Name:             n,
+			Type:             f.ParamTypes[i],
+			AllowDynamicType: true,
+			AllowNull:        true,
+		})
+	}
+	return function.New(&function.Spec{
+		Description: fmt.Sprintf("function.%s declared at %s", f.Name(), f.HclBlock().Range().String()),
+		Params:      params,
+		Type:        function.StaticReturnType(f.ResultType),
+		Impl:        f.call,
+	}), nil
+}
+
+func (f *FunctionBlock) call(args []cty.Value, retType cty.Type) (cty.Value, error) {
+	ctx := f.c.EmptyEvalContext()
+	for i, n := range f.Params {
+		ctx.Variables[n] = args[i]
+	}
+	v, diag := f.result.Value(ctx)
+	if diag.HasErrors() {
+		return cty.NilVal, diag
+	}
+	if retType == cty.DynamicPseudoType {
+		return v, nil
+	}
+	converted, err := convert.Convert(v, retType)
+	if err != nil {
+		return cty.NilVal, fmt.Errorf("incompatible result of function.%s, want %s: %+v", f.Name(), retType.FriendlyName(), err)
+	}
+	return converted, nil
+}
+
+// checkRecursion returns an error if the function calls itself, directly or via other user functions.
+func (f *FunctionBlock) checkRecursion() error {
+	functions := make(map[string]*FunctionBlock)
+	for _, fb := range Blocks[*FunctionBlock](f.c) {
+		functions[fb.Name()] = fb
+	}
+	var path []string
+	visited := make(map[string]bool)
+	var visit func(name string) bool
+	visit = func(name string) bool {
+		path = append(path, name)
+		for _, callee := range functions[name].calls() {
+			if callee == f.Name() {
+				path = append(path, callee)
+				return true
+			}
+			if _, ok := functions[callee]; !ok || visited[callee] {
+				continue
+			}
+			visited[callee] = true
+			if visit(callee) {
+				return true
+			}
+		}
+		path = path[:len(path)-1]
+		return false
+	}
+	if visit(f.Name()) {
+		return hcl.Diagnostics{f.diagnostic("Recursive function call", fmt.Sprintf("recursive call is not allowed: %s", strings.Join(path, " -> ")), f.HclBlock().Range().Ptr())}
+	}
+	return nil
+}
+
+// calls returns sorted names of functions called by the result expression.
+func (f *FunctionBlock) calls() []string {
+	attr, ok := f.HclBlock().Body.Attributes["result"]
+	if !ok {
+		return nil
+	}
+	names := make(map[string]struct{})
+	_ = hclsyntax.VisitAll(attr.Expr, func(node hclsyntax.Node) hcl.Diagnostics {
+		if call, ok := node.(*hclsyntax.FunctionCallExpr); ok {
+			names[call.Name] = struct{}{}
+		}
+		return nil
+	})
+	return sortedKeys(names)
+}
+
+func (f *FunctionBlock) isParam(name string) bool {
+	for _, p := range f.Params {
+		if p == name {
+			return true
+		}
+	}
+	return false
+}
+
+func (f *FunctionBlock) diagnostic(summary, detail string, subject *hcl.Range) *hcl.Diagnostic {
+	return &hcl.Diagnostic{
+		Severity: hcl.DiagError,
+		Summary:  summary,
+		Detail:   detail,
+		Subject:  subject,
+	}
+}
+
+func (f *FunctionBlock) Type() string {
+	return ""
+}
+
+func (f *FunctionBlock) BlockType() string {
+	return "function"
+}
+
+func (f *FunctionBlock) AddressLength() int { return 2 }
+
+func (f *FunctionBlock) CanExecutePrePlan() bool {
+	return true
+}
+
+// userFunctions returns compiled functions declared by `function` blocks, functions with compile errors are skipped.
+func (c *BaseConfig) userFunctions() map[string]function.Function {
+	r := make(map[string]function.Function)
+	for _, fb := range Blocks[*FunctionBlock](c) {
+		if fn, err := fb.function(); err == nil {
+			r[fb.Name()] = fn
+		}
+	}
+	return r
+}
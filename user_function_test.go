@@ -0,0 +1,179 @@
+package golden
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"github.com/zclconf/go-cty/cty"
+	"github.com/zclconf/go-cty/cty/function"
+	"github.com/zclconf/go-cty/cty/function/stdlib"
+)
+
+type userFunctionSuite struct {
+	suite.Suite
+	*testBase
+}
+
+func TestUserFunctionSuite(t *testing.T) {
+	suite.Run(t, new(userFunctionSuite))
+}
+
+func (s *userFunctionSuite) SetupTest() {
+	s.testBase = newTestBase()
+}
+
+func (s *userFunctionSuite) TearDownTest() {
+	s.teardown()
+}
+
+func (s *userFunctionSuite) TestUserFunction_CallFromLocalsVariablesAndOtherFunctions() {
+	s.dummyFsWithFiles(map[string]string{
+		"/main.hcl": `function "add" {
+  params      = [a, b]
+  param_types = [number, number]
+  result_type = number
+  result      = a + b
+}
+
+function "greet" {
+  params = [name]
+  result = "hello ${upper(name)}, ${add(1, 2)}"
+}
+
+variable "v" {
+  default = add(1, 1)
+}
+
+locals {
+  greeting = greet("world")
+  sum      = add(var.v, "3")
+}
+`,
+	})
+	config, err := BuildDummyConfig("/", "/", nil, nil)
+	require.NoError(s.T(), err)
+	_, err = RunDummyPlan(config)
+	require.NoError(s.T(), err)
+	locals := config.EvalContext().Variables["local"]
+	s.Equal(cty.StringVal("hello WORLD, 3"), locals.GetAttr("greeting"))
+	s.True(locals.GetAttr("sum").Equals(cty.NumberIntVal(5)).True())
+	fn, ok := config.EmptyEvalContext().Functions["add"]
+	require.True(s.T(), ok)
+	s.Equal("a", fn.Params()[0].Name)
+	s.Equal(cty.Number, fn.Params()[0].Type)
+}
+
+func (s *userFunctionSuite) TestUserFunction_InvalidArgumentType() {
+	s.dummyFsWithFiles(map[string]string{
+		"/main.hcl": `function "add" {
+  params      = [a, b]
+  param_types = [number, number]
+  result      = a + b
+}
+
+locals {
+  sum = add("a", 1)
+}
+`,
+	})
+	_, err := BuildDummyConfig("/", "/", nil, nil)
+	require.NotNil(s.T(), err)
+	s.Contains(err.Error(), "Invalid function argument")
+}
+
+func (s *userFunctionSuite) TestUserFunction_Misuse() {
+	cases := []struct {
+		desc     string
+		function string
+		want     string
+	}{
+		{
+			desc: "reference other blocks",
+			function: `function "f" {
+  params = [a]
+  result = a + local.b
+}`,
+			want: "function.f can only reference its params, `local.b` is not allowed",
+		},
+		{
+			desc: "invalid param",
+			function: `function "f" {
+  params = ["a"]
+  result = 1
+}`,
+			want: "params must be a list of bare identifiers",
+		},
+		{
+			desc: "param types mismatch",
+			function: `function "f" {
+  params      = [a, b]
+  param_types = [number]
+  result      = a
+}`,
+			want: "function.f declares 2 params but 1 param_types",
+		},
+		{
+			desc: "missing result",
+			function: `function "f" {
+  params = [a]
+}`,
+			want: "function.f requires `result` attribute",
+		},
+		{
+			desc: "conflict with built-in",
+			function: `function "upper" {
+  params = [a]
+  result = a
+}`,
+			want: "function.upper conflicts with built-in function `upper`",
+		},
+		{
+			desc: "recursion",
+			function: `function "f" {
+  params = [a]
+  result = g(a)
+}
+
+function "g" {
+  params = [a]
+  result = f(a)
+}`,
+			want: "recursive call is not allowed: f -> g -> f",
+		},
+	}
+	for _, c := range cases {
+		s.Run(c.desc, func() {
+			s.dummyFsWithFiles(map[string]string{
+				"/main.hcl": c.function,
+			})
+			_, err := BuildDummyConfig("/", "/", nil, nil)
+			require.NotNil(s.T(), err)
+			s.Contains(err.Error(), c.want)
+		})
+	}
+}
+
+func (s *userFunctionSuite) TestUserFunction_ConflictWithDslFunction() {
+	for _, name := range []string{"greet", "provider::azure::greet"} {
+		s.Run(name, func() {
+			s.dummyFsWithFiles(map[string]string{
+				"/main.hcl": fmt.Sprintf(`function %q {
+  params = [a]
+  result = a
+}
+`, name),
+			})
+			c := NewBasicConfig("/", "faketerraform", "ft", nil, nil, nil)
+			c.OverrideFunctions = map[string]function.Function{
+				"greet": stdlib.UpperFunc,
+			}
+			require.NoError(s.T(), c.RegisterFunction("provider::azure", "greet", stdlib.UpperFunc))
+			blocks, err := LoadHclBlocks(false, "/")
+			require.NoError(s.T(), err)
+			err = InitConfig(c, blocks)
+			s.ErrorContains(err, fmt.Sprintf("function.%s conflicts with function `%s` provided by faketerraform", name, name))
+		})
+	}
+}
@@ -1,12 +1,25 @@
 package golden
 
 import (
+	"bufio"
+	"bytes"
+	"encoding/json"
 	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+
 	"github.com/hashicorp/hcl/v2"
 	"github.com/hashicorp/hcl/v2/hclparse"
-	"path/filepath"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/zclconf/go-cty/cty"
+	"gopkg.in/yaml.v3"
 )
 
+// varFileSuffixes are appended to `<dsl>.<abbr>vars` and `*.auto.<abbr>vars` when discovering var files, later suffix takes precedence.
+var varFileSuffixes = []string{"", ".json", ".yaml", ".yml", ".env"}
+
 type varFileParser interface {
 	ParseFile(content []byte, fileName string) (*hcl.File, error)
 }
@@ -29,6 +42,153 @@ func (j jsonFileParser) ParseFile(content []byte, fileName string) (*hcl.File, e
 	return file, nil
 }
 
+var _ varFileParser = yamlFileParser{}
+
+type yamlFileParser struct {
+	dslAbbreviation string
+}
+
+// ParseFile decodes yaml content into a yaml.Node once, values are converted into cty with the same rules as json var files,
+// and ranges of attributes point to the yaml keys rather than the converted json.
+func (y yamlFileParser) ParseFile(content []byte, fileName string) (*hcl.File, error) {
+	ext := filepath.Ext(fileName)
+	if ext != ".yaml" && ext != ".yml" {
+		return nil, nil
+	}
+	var doc yaml.Node
+	if err := yaml.Unmarshal(content, &doc); err != nil {
+		return nil, fmt.Errorf("cannot parse yaml file %s: %+v", fileName, err)
+	}
+	var value any = map[string]any{}
+	if len(doc.Content) > 0 {
+		if err := doc.Content[0].Decode(&value); err != nil {
+			return nil, fmt.Errorf("cannot parse yaml file %s: %+v", fileName, err)
+		}
+	}
+	jsonContent, err := json.Marshal(value)
+	if err != nil {
+		return nil, fmt.Errorf("cannot convert yaml file %s: %+v", fileName, err)
+	}
+	parser := hclparse.NewParser()
+	file, diag := parser.ParseJSON(jsonContent, fileName)
+	if diag.HasErrors() {
+		return nil, diag
+	}
+	file.Body = yamlBody{Body: file.Body, keyRanges: yamlKeyRanges(&doc, fileName)}
+	return file, nil
+}
+
+// yamlKeyRanges returns ranges of top level keys of the yaml document.
+func yamlKeyRanges(doc *yaml.Node, fileName string) map[string]hcl.Range {
+	ranges := make(map[string]hcl.Range)
+	if len(doc.Content) == 0 || doc.Content[0].Kind != yaml.MappingNode {
+		return ranges
+	}
+	m := doc.Content[0]
+	for i := 0; i+1 < len(m.Content); i += 2 {
+		key := m.Content[i]
+		ranges[key.Value] = hcl.Range{
+			Filename: fileName,
+			Start:    hcl.Pos{Line: key.Line, Column: key.Column},
+			End:      hcl.Pos{Line: key.Line, Column: key.Column + len(key.Value)},
+		}
+	}
+	return ranges
+}
+
+// yamlBody replaces ranges of attributes parsed from the converted json with ranges of the yaml keys.
+type yamlBody struct {
+	hcl.Body
+	keyRanges map[string]hcl.Range
+}
+
+func (b yamlBody) JustAttributes() (hcl.Attributes, hcl.Diagnostics) {
+	attributes, diag := b.Body.JustAttributes()
+	for n, attr := range attributes {
+		// keys the yaml parser cannot locate have no position rather than a position in the converted json.
+		r := b.keyRanges[n]
+		attributes[n] = &hcl.Attribute{
+			Name:      attr.Name,
+			Expr:      attr.Expr,
+			Range:     r,
+			NameRange: r,
+		}
+	}
+	return attributes, diag
+}
+
+var _ varFileParser = dotEnvFileParser{}
+
+type dotEnvFileParser struct {
+	dslAbbreviation string
+}
+
+// ParseFile translates every `KEY=VALUE` line into a hcl attribute on the same line, so ranges still point to the original file.
+// Quoted values are always strings, unquoted values are parsed like command line assigned values, as literal expressions or fallback to strings.
+func (d dotEnvFileParser) ParseFile(content []byte, fileName string) (*hcl.File, error) {
+	if filepath.Ext(fileName) != ".env" {
+		return nil, nil
+	}
+	sb := strings.Builder{}
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	lineNumber := 0
+	for scanner.Scan() {
+		lineNumber++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			sb.WriteString("\n")
+			continue
+		}
+		line = strings.TrimPrefix(line, "export ")
+		key, rawValue, ok := strings.Cut(line, "=")
+		key = strings.TrimSpace(key)
+		if !ok || !hclsyntax.ValidIdentifier(key) {
+			return nil, fmt.Errorf("invalid line in %s:%d, want `KEY=VALUE`", fileName, lineNumber)
+		}
+		expr, err := dotEnvValueExpression(strings.TrimSpace(rawValue))
+		if err != nil {
+			return nil, fmt.Errorf("invalid value in %s:%d: %+v", fileName, lineNumber, err)
+		}
+		fmt.Fprintf(&sb, "%s = %s\n", key, expr)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("cannot read %s: %+v", fileName, err)
+	}
+	parser := hclparse.NewParser()
+	file, diag := parser.ParseHCL([]byte(sb.String()), fileName)
+	if diag.HasErrors() {
+		return nil, diag
+	}
+	return file, nil
+}
+
+func dotEnvValueExpression(rawValue string) (string, error) {
+	stringExpr := func(s string) string {
+		return string(hclwrite.TokensForValue(cty.StringVal(s)).Bytes())
+	}
+	if len(rawValue) >= 2 && rawValue[0] == '\'' && rawValue[len(rawValue)-1] == '\'' {
+		return stringExpr(rawValue[1 : len(rawValue)-1]), nil
+	}
+	if len(rawValue) >= 2 && rawValue[0] == '"' && rawValue[len(rawValue)-1] == '"' {
+		s, err := strconv.Unquote(rawValue)
+		if err != nil {
+			return "", err
+		}
+		return stringExpr(s), nil
+	}
+	if rawValue == "" {
+		return stringExpr(""), nil
+	}
+	expr, diag := hclsyntax.ParseExpression([]byte(rawValue), "", hcl.InitialPos)
+	if diag.HasErrors() || strings.Contains(rawValue, "\n") {
+		return stringExpr(rawValue), nil
+	}
+	if _, diag = expr.Value(nil); diag.HasErrors() {
+		return stringExpr(rawValue), nil
+	}
+	return rawValue, nil
+}
+
 var _ varFileParser = hclFileParser{}
 
 type hclFileParser struct {
@@ -36,7 +196,8 @@ type hclFileParser struct {
 }
 
 func (h hclFileParser) ParseFile(content []byte, fileName string) (*hcl.File, error) {
-	if filepath.Ext(fileName) == ".json" {
+	switch filepath.Ext(fileName) {
+	case ".json", ".yaml", ".yml", ".env":
 		return nil, nil
 	}
 	parser := hclparse.NewParser()
@@ -54,15 +215,17 @@ type varFileParserImpl struct {
 }
 
 func (h varFileParserImpl) ParseFile(content []byte, fileName string) (*hcl.File, error) {
-	hclParser := hclFileParser{dslAbbreviation: h.dslAbbreviation} //nolint:gosimple,staticcheck
-	file, err := hclParser.ParseFile(content, fileName)
-	if file != nil || err != nil {
-		return file, err
+	parsers := []varFileParser{
+		hclFileParser{dslAbbreviation: h.dslAbbreviation},    //nolint:gosimple,staticcheck
+		jsonFileParser{dslAbbreviation: h.dslAbbreviation},   //nolint:gosimple,staticcheck
+		yamlFileParser{dslAbbreviation: h.dslAbbreviation},   //nolint:gosimple,staticcheck
+		dotEnvFileParser{dslAbbreviation: h.dslAbbreviation}, //nolint:gosimple,staticcheck
 	}
-	jsonParser := jsonFileParser{dslAbbreviation: h.dslAbbreviation} //nolint:gosimple,staticcheck
-	file, err = jsonParser.ParseFile(content, fileName)
-	if file != nil || err != nil {
-		return file, err
+	for _, parser := range parsers {
+		file, err := parser.ParseFile(content, fileName)
+		if file != nil || err != nil {
+			return file, err
+		}
 	}
-	return nil, fmt.Errorf("incorrect file %s: %+v", fileName, err)
+	return nil, fmt.Errorf("incorrect file %s", fileName)
 }
@@ -5,8 +5,8 @@ import (
 	"github.com/hashicorp/go-multierror"
 	"github.com/hashicorp/hcl/v2"
 	"github.com/hashicorp/hcl/v2/ext/typeexpr"
-	"github.com/hashicorp/hcl/v2/gohcl"
 	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/lonegunmanb/hclfuncs/marks"
 	"github.com/zclconf/go-cty/cty"
 	"github.com/zclconf/go-cty/cty/convert"
 	"os"
@@ -31,18 +31,25 @@ type VariableValidation struct {
 
 type VariableBlock struct {
 	*BaseBlock
-	Description   *string
+	Description *string
+	// Sensitive variables have their values marked as sensitive, like values returned by `sensitive()`.
+	Sensitive     bool
 	Validations   []VariableValidation
 	variableType  *cty.Type
 	variableValue *cty.Value
+	valueRead     VariableValueRead
 }
 
 func (v *VariableBlock) Decode(block *HclBlock, context *hcl.EvalContext) error {
 	return nil
 }
 
+// ExecuteDuringPlan resolves the variable if its validations depend on blocks that could not be evaluated before plan.
 func (v *VariableBlock) ExecuteDuringPlan() error {
-	return nil
+	if v.variableValue != nil {
+		return nil
+	}
+	return v.resolve()
 }
 
 func (v *VariableBlock) Type() string {
@@ -66,6 +73,12 @@ func (v *VariableBlock) AddressLength() int {
 }
 
 func (v *VariableBlock) CanExecutePrePlan() bool {
+	upstreams, _ := v.c.GetAncestors(v.Address())
+	for _, i := range upstreams {
+		if !i.(Block).CanExecutePrePlan() {
+			return false
+		}
+	}
 	return true
 }
 
@@ -79,10 +92,20 @@ func (v *VariableBlock) Value() cty.Value {
 func (v *VariableBlock) Variable() {}
 
 func (v *VariableBlock) ExecuteBeforePlan() error {
+	if !v.CanExecutePrePlan() {
+		return nil
+	}
+	return v.resolve()
+}
+
+func (v *VariableBlock) resolve() error {
 	err := v.parseDescription()
 	if err != nil {
 		return err
 	}
+	if err = v.parseSensitive(); err != nil {
+		return err
+	}
 	if err = v.parseVariableType(); err != nil {
 		return err
 	}
@@ -93,6 +116,7 @@ func (v *VariableBlock) ExecuteBeforePlan() error {
 	if variableRead.Error != nil {
 		return variableRead.Error
 	}
+	v.valueRead = variableRead
 	value := variableRead.Value
 	if value == nil {
 		return fmt.Errorf("cannot evaluate value for var.%s", v.Name())
@@ -104,7 +128,13 @@ func (v *VariableBlock) ExecuteBeforePlan() error {
 		}
 		value = &convertedValue
 	}
+	if v.Sensitive {
+		// marked values are redacted in logs, console, plan results, plan diffs, saved plans and states.
+		marked := value.Mark(marks.Sensitive)
+		value = &marked
+	}
 	v.variableValue = value
+	configLogger(v.c).Debug("resolved variable", "address", v.Address(), "source", variableRead.Source.String(), "value", logValue(*value, v.Sensitive))
 	return v.validationCheck()
 }
 
@@ -139,8 +169,15 @@ func (v *VariableBlock) readValue() (VariableValueRead, error) {
 }
 
 func (v *VariableBlock) readValueFromEnv() VariableValueRead {
-	env := os.Getenv(fmt.Sprintf("%s_VAR_%s", strings.ToUpper(v.c.DslAbbreviation()), v.name))
-	return v.parseVariableValueFromString(env, true)
+	envName := fmt.Sprintf("%s_VAR_%s", strings.ToUpper(v.c.DslAbbreviation()), v.name)
+	read := v.parseVariableValueFromString(os.Getenv(envName), true)
+	if read == NoValue {
+		return NoValue
+	}
+	return read.withSource(VariableValueSource{
+		Type:    VariableValueSourceEnv,
+		EnvName: envName,
+	})
 }
 
 func (v *VariableBlock) readDefaultValue() VariableValueRead {
@@ -148,11 +185,33 @@ func (v *VariableBlock) readDefaultValue() VariableValueRead {
 	if !hasDefault {
 		return NoValue
 	}
-	value, diag := defaultAttr.Expr.Value(nil)
+	source := VariableValueSource{
+		Type:     VariableValueSourceDefault,
+		FileName: defaultAttr.SrcRange.Filename,
+		Range:    defaultAttr.SrcRange,
+	}
+	builtinRoots := builtinRootNames(v.c)
+	for _, t := range defaultAttr.Expr.Variables() {
+		if _, ok := builtinRoots[t.RootName()]; ok {
+			continue
+		}
+		return NewVariableValueRead(v.Name(), nil, hcl.Diagnostics{&hcl.Diagnostic{
+			Severity: hcl.DiagError,
+			Summary:  "Variables not allowed",
+			Detail:   fmt.Sprintf("default value of var.%s cannot reference `%s`, only functions and built-in values can be used in default value", v.Name(), traversalString(t)),
+			Subject:  t.SourceRange().Ptr(),
+		}}).withSource(source)
+	}
+	// default value could call functions and reference built-in values like `path.module`, but cannot reference other blocks.
+	ctx := new(hcl.EvalContext)
+	if v.c != nil {
+		ctx = v.c.EmptyEvalContext()
+	}
+	value, diag := defaultAttr.Expr.Value(ctx)
 	if diag.HasErrors() {
-		return NewVariableValueRead(v.Name(), nil, diag)
+		return NewVariableValueRead(v.Name(), nil, diag).withSource(source)
 	}
-	return NewVariableValueRead(v.Name(), &value, nil)
+	return NewVariableValueRead(v.Name(), &value, nil).withSource(source)
 }
 
 func (v *VariableBlock) parseVariableValueFromString(rawValue string, treatEmptyAsNoValue bool) VariableValueRead {
@@ -190,7 +249,9 @@ func (v *VariableBlock) readFromPromote() (VariableValueRead, error) {
 		return NoValue, err
 	}
 	_, _ = valuePromoter.printf("\n")
-	return v.parseVariableValueFromString(in, false), nil
+	return v.parseVariableValueFromString(in, false).withSource(VariableValueSource{
+		Type: VariableValueSourcePrompt,
+	}), nil
 }
 
 func (v *VariableBlock) parseDescription() error {
@@ -210,20 +271,32 @@ func (v *VariableBlock) parseDescription() error {
 	return nil
 }
 
+func (v *VariableBlock) parseSensitive() error {
+	attr, ok := v.HclBlock().Attributes()["sensitive"]
+	if !ok {
+		return nil
+	}
+	value, diag := attr.Expr.Value(nil)
+	if diag.HasErrors() {
+		return diag
+	}
+	if value.Type() != cty.Bool || value.IsNull() {
+		return fmt.Errorf("incorrect type for `sensitive` %s, got %s, want %s", attr.Range().String(), value.Type().GoString(), cty.Bool.GoString())
+	}
+	v.Sensitive = value.True()
+	return nil
+}
+
 func (v *VariableBlock) validationCheck() error {
 	var err error
 	for _, nb := range v.HclBlock().NestedBlocks() {
 		if nb.Type != "validation" {
 			continue
 		}
-		ctx := v.c.EmptyEvalContext()
-		ctx.Variables = map[string]cty.Value{
-			"var": cty.ObjectVal(map[string]cty.Value{
-				v.Name(): *v.variableValue,
-			}),
-		}
+		// other variables and locals referenced by validations are upstreams in the dag, so they've been resolved already.
+		ctx := v.EvalContext()
 		var vb VariableValidation
-		diag := gohcl.DecodeBody(nb.Body, ctx, &vb)
+		diag := decodeBody(nb.Body, ctx, &vb)
 		if diag.HasErrors() {
 			err = multierror.Append(err, diag)
 			continue
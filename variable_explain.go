@@ -0,0 +1,69 @@
+package golden
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/zclconf/go-cty/cty"
+)
+
+// VariableValueExplanation describes where the final value of a variable came from, and which values with lower precedence it overrode.
+type VariableValueExplanation struct {
+	Name   string
+	Value  cty.Value
+	Source VariableValueSource
+	// Sensitive is true if the variable is declared with `sensitive = true`, all its values are redacted in String.
+	Sensitive bool
+	// Overridden are ordered from the highest precedence to the lowest.
+	Overridden []VariableValueRead
+}
+
+func (e VariableValueExplanation) String() string {
+	sb := strings.Builder{}
+	fmt.Fprintf(&sb, "var.%s = %s (from %s)", e.Name, e.explainValue(e.Value), e.Source.String())
+	for _, o := range e.Overridden {
+		value := "<error>"
+		if o.Value != nil {
+			value = e.explainValue(*o.Value)
+		}
+		fmt.Fprintf(&sb, "\n  overrides %s (from %s)", value, o.Source.String())
+	}
+	return sb.String()
+}
+
+func (e VariableValueExplanation) explainValue(v cty.Value) string {
+	if e.Sensitive || v.ContainsMarked() {
+		return logValue(v, true)
+	}
+	return CtyValueToString(v)
+}
+
+// ExplainVariable explains the final value of variable `name`, it must be called after the variable has been resolved by `RunPrePlan` or `RunPlan`.
+func (c *BaseConfig) ExplainVariable(name string) (*VariableValueExplanation, error) {
+	address := fmt.Sprintf("var.%s", name)
+	vertex, err := c.d.GetVertex(address)
+	if err != nil || vertex == nil {
+		return nil, fmt.Errorf("cannot find %s", address)
+	}
+	vb, ok := vertex.(*VariableBlock)
+	if !ok {
+		return nil, fmt.Errorf("%s is not a variable", address)
+	}
+	read := vb.valueRead
+	if read == NoValue || vb.variableValue == nil {
+		return nil, fmt.Errorf("%s has not been resolved yet", address)
+	}
+	overridden := read.Overridden()
+	if read.Source.Type != VariableValueSourceDefault {
+		if defaultRead := vb.readDefaultValue(); defaultRead != NoValue {
+			overridden = append(overridden, defaultRead)
+		}
+	}
+	return &VariableValueExplanation{
+		Name:       name,
+		Value:      *vb.variableValue,
+		Source:     read.Source,
+		Sensitive:  vb.Sensitive,
+		Overridden: overridden,
+	}, nil
+}
@@ -0,0 +1,117 @@
+package golden
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"github.com/zclconf/go-cty/cty"
+)
+
+type variableExplainSuite struct {
+	suite.Suite
+	*testBase
+}
+
+func TestVariableExplainSuite(t *testing.T) {
+	suite.Run(t, new(variableExplainSuite))
+}
+
+func (s *variableExplainSuite) SetupTest() {
+	s.testBase = newTestBase()
+}
+
+func (s *variableExplainSuite) TearDownTest() {
+	s.teardown()
+}
+
+func (s *variableExplainSuite) TestExplainVariable_LayeredSources() {
+	s.T().Setenv("FT_VAR_test", "from_env")
+	s.dummyFsWithFiles(map[string]string{
+		"/test.hcl": `variable "test" {
+  default = "from_default"
+}`,
+		"/faketerraform.ftvars": `test = "from_default_var_file"`,
+		"/a.auto.ftvars":        `test = "from_auto_var_file"`,
+	})
+	config, err := BuildDummyConfig("/", "/", []CliFlagAssignedVariables{
+		NewCliFlagAssignedVariable("test", "from_cli_0"),
+		NewCliFlagAssignedVariable("test", "from_cli_1"),
+	}, nil)
+	require.NoError(s.T(), err)
+	explanation, err := config.(*DummyConfig).ExplainVariable("test")
+	require.NoError(s.T(), err)
+	s.Equal(cty.StringVal("from_cli_1"), explanation.Value)
+	s.Equal(VariableValueSourceCliFlag, explanation.Source.Type)
+	s.Equal(1, explanation.Source.CliFlagIndex)
+
+	var overridden []VariableValueSourceType
+	for _, o := range explanation.Overridden {
+		overridden = append(overridden, o.Source.Type)
+	}
+	s.Equal([]VariableValueSourceType{
+		VariableValueSourceCliFlag,
+		VariableValueSourceAutoVarFile,
+		VariableValueSourceVarFile,
+		VariableValueSourceEnv,
+		VariableValueSourceDefault,
+	}, overridden)
+	s.Equal(0, explanation.Overridden[0].Source.CliFlagIndex)
+	s.Equal("/a.auto.ftvars", explanation.Overridden[1].Source.FileName)
+	s.Equal("FT_VAR_test", explanation.Overridden[3].Source.EnvName)
+	s.Contains(explanation.String(), "var.test = from_cli_1 (from command line flag #1)")
+	s.Contains(explanation.String(), "overrides from_auto_var_file (from auto var file /a.auto.ftvars:1,1)")
+}
+
+func (s *variableExplainSuite) TestExplainVariable_DefaultValueOnly() {
+	s.dummyFsWithFiles(map[string]string{
+		"/test.hcl": `variable "test" {
+  default = "from_default"
+}`,
+	})
+	config, err := BuildDummyConfig("/", "/", nil, nil)
+	require.NoError(s.T(), err)
+	explanation, err := config.(*DummyConfig).ExplainVariable("test")
+	require.NoError(s.T(), err)
+	s.Equal(VariableValueSourceDefault, explanation.Source.Type)
+	s.Equal(2, explanation.Source.Range.Start.Line)
+	s.Empty(explanation.Overridden)
+}
+
+func (s *variableExplainSuite) TestExplainVariable_UnknownVariable() {
+	s.dummyFsWithFiles(map[string]string{
+		"/test.hcl": `locals {
+  a = "a"
+}`,
+	})
+	config, err := BuildDummyConfig("/", "/", nil, nil)
+	require.NoError(s.T(), err)
+	_, err = config.(*DummyConfig).ExplainVariable("test")
+	s.ErrorContains(err, "cannot find var.test")
+}
+
+func (s *variableExplainSuite) TestExplainVariable_SensitiveValues() {
+	s.dummyFsWithFiles(map[string]string{
+		"/test.hcl": `variable "test" {
+  default = sensitive("from_default")
+}
+
+variable "password" {
+  default   = "from_default"
+  sensitive = true
+}`,
+	})
+	config, err := BuildDummyConfig("/", "/", []CliFlagAssignedVariables{
+		NewCliFlagAssignedVariable("test", "from_cli"),
+		NewCliFlagAssignedVariable("password", "from_cli"),
+	}, nil)
+	require.NoError(s.T(), err)
+	explanation, err := config.(*DummyConfig).ExplainVariable("test")
+	require.NoError(s.T(), err)
+	s.Equal("var.test = from_cli (from command line flag #0)\n  overrides (sensitive value) (from default value /test.hcl:2,3)", explanation.String())
+
+	explanation, err = config.(*DummyConfig).ExplainVariable("password")
+	require.NoError(s.T(), err)
+	s.NotContains(explanation.String(), "from_")
+	s.Contains(explanation.String(), "var.password = (sensitive value) (from command line flag #1)")
+}
@@ -1,13 +1,78 @@
 package golden
 
-import "github.com/zclconf/go-cty/cty"
+import (
+	"fmt"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/zclconf/go-cty/cty"
+)
 
 var NoValue = VariableValueRead{}
 
+type VariableValueSourceType string
+
+const (
+	VariableValueSourceEnv            VariableValueSourceType = "env"
+	VariableValueSourceVarFile        VariableValueSourceType = "var_file"
+	VariableValueSourceAutoVarFile    VariableValueSourceType = "auto_var_file"
+	VariableValueSourceCliFlag        VariableValueSourceType = "cli_flag"
+	VariableValueSourceCliFlagVarFile VariableValueSourceType = "cli_flag_var_file"
+	VariableValueSourceDefault        VariableValueSourceType = "default"
+	VariableValueSourcePrompt         VariableValueSourceType = "prompt"
+	VariableValueSourceModuleArgument VariableValueSourceType = "module_argument"
+	VariableValueSourceSavedPlan      VariableValueSourceType = "saved_plan"
+)
+
+// VariableValueSource records where a variable value was read from.
+type VariableValueSource struct {
+	Type    VariableValueSourceType
+	EnvName string
+	// FileName and Range are set for values read from var files, `default` attributes and module arguments.
+	FileName string
+	Range    hcl.Range
+	// CliFlagIndex is the position of the flag in `CliFlagAssignedVariables`, set for values assigned by command line flags.
+	CliFlagIndex int
+}
+
+func (s VariableValueSource) String() string {
+	switch s.Type {
+	case VariableValueSourceEnv:
+		return fmt.Sprintf("environment variable %s", s.EnvName)
+	case VariableValueSourceVarFile:
+		return fmt.Sprintf("var file %s", s.location())
+	case VariableValueSourceAutoVarFile:
+		return fmt.Sprintf("auto var file %s", s.location())
+	case VariableValueSourceCliFlag:
+		return fmt.Sprintf("command line flag #%d", s.CliFlagIndex)
+	case VariableValueSourceCliFlagVarFile:
+		return fmt.Sprintf("command line flag #%d, var file %s", s.CliFlagIndex, s.location())
+	case VariableValueSourceDefault:
+		return fmt.Sprintf("default value %s", s.location())
+	case VariableValueSourcePrompt:
+		return "interactive prompt"
+	case VariableValueSourceModuleArgument:
+		return fmt.Sprintf("module argument %s", s.location())
+	case VariableValueSourceSavedPlan:
+		return fmt.Sprintf("saved plan %s", s.FileName)
+	default:
+		return "unknown source"
+	}
+}
+
+func (s VariableValueSource) location() string {
+	if s.Range.Start.Line == 0 {
+		return s.FileName
+	}
+	return fmt.Sprintf("%s:%d,%d", s.FileName, s.Range.Start.Line, s.Range.Start.Column)
+}
+
 type VariableValueRead struct {
-	Name  string
-	Value *cty.Value
-	Error error
+	Name   string
+	Value  *cty.Value
+	Error  error
+	Source VariableValueSource
+	// overridden is the read with lower precedence that this read replaced, if any.
+	overridden *VariableValueRead
 }
 
 func NewVariableValueRead(name string, value *cty.Value, err error) VariableValueRead {
@@ -21,3 +86,56 @@ func NewVariableValueRead(name string, value *cty.Value, err error) VariableValu
 func (r VariableValueRead) HasError() bool {
 	return r.Error == nil
 }
+
+// Overridden returns reads replaced by this read, ordered from the highest precedence to the lowest.
+func (r VariableValueRead) Overridden() []VariableValueRead {
+	var reads []VariableValueRead
+	for o := r.overridden; o != nil; o = o.overridden {
+		reads = append(reads, *o)
+	}
+	return reads
+}
+
+func (r VariableValueRead) withSource(source VariableValueSource) VariableValueRead {
+	r.Source = source
+	return r
+}
+
+// overriding appends old to the tail of the overridden chain, so reads merged earlier keep their own history.
+func (r VariableValueRead) overriding(old VariableValueRead) VariableValueRead {
+	if r.overridden == nil {
+		r.overridden = &old
+		return r
+	}
+	o := r.overridden.overriding(old)
+	r.overridden = &o
+	return r
+}
+
+func withSourceType(reads map[string]VariableValueRead, t VariableValueSourceType) map[string]VariableValueRead {
+	for n, read := range reads {
+		read.Source.Type = t
+		reads[n] = read
+	}
+	return reads
+}
+
+// mergeVariableReads works like merge, but reads with higher precedence keep track of reads they replaced.
+func mergeVariableReads(maps ...map[string]VariableValueRead) map[string]VariableValueRead {
+	r := make(map[string]VariableValueRead)
+	for _, m := range maps {
+		for n, read := range m {
+			if read == NoValue {
+				if _, ok := r[n]; !ok {
+					r[n] = read
+				}
+				continue
+			}
+			if old, ok := r[n]; ok && old != NoValue {
+				read = read.overriding(old)
+			}
+			r[n] = read
+		}
+	}
+	return r
+}
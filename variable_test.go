@@ -2,14 +2,20 @@ package golden
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"github.com/hashicorp/hcl/v2"
 	"github.com/hashicorp/hcl/v2/hclsyntax"
 	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/lonegunmanb/hclfuncs/marks"
 	"github.com/prashantv/gostub"
+	"github.com/spf13/afero"
 	"github.com/stretchr/testify/require"
 	"github.com/stretchr/testify/suite"
 	"github.com/zclconf/go-cty/cty"
+	"github.com/zclconf/go-cty/cty/function"
+	"os"
+	"path/filepath"
 	"regexp"
 	"strings"
 	"testing"
@@ -135,11 +141,71 @@ func (s *variableSuite) TestReadDefaultValue() {
 				},
 			}
 			read := sut.readDefaultValue()
-			s.Equal(c.expected, read)
+			s.Equal(c.expected.Name, read.Name)
+			s.Equal(c.expected.Value, read.Value)
+			s.Equal(c.expected.Error, read.Error)
+			if c.expected != NoValue {
+				s.Equal(VariableValueSourceDefault, read.Source.Type)
+				s.Equal("test.hcl", read.Source.FileName)
+			}
 		})
 	}
 }
 
+func (s *variableSuite) TestReadDefaultValue_WithFunctions() {
+	dir := s.T().TempDir()
+	require.NoError(s.T(), os.WriteFile(filepath.Join(dir, "x.json"), []byte(`{"name": "hello"}`), 0644))
+	cases := []struct {
+		desc     string
+		config   string
+		expected cty.Value
+	}{
+		{
+			desc: "string function",
+			config: `variable "test" {
+  default = lower("X")
+}`,
+			expected: cty.StringVal("x"),
+		},
+		{
+			desc: "file function",
+			config: `variable "test" {
+  default = jsondecode(file("x.json")).name
+}`,
+			expected: cty.StringVal("hello"),
+		},
+	}
+	for _, c := range cases {
+		s.Run(c.desc, func() {
+			s.dummyFsWithFiles(map[string]string{
+				"test.hcl": c.config,
+			})
+			hclBlocks, err := loadHclBlocks(false, "")
+			require.NoError(s.T(), err)
+			config, err := NewDummyConfig(dir, context.TODO(), hclBlocks, nil)
+			require.NoError(s.T(), err)
+			vb := config.GetVertices()["var.test"].(*VariableBlock)
+			s.Equal(c.expected, *vb.variableValue)
+		})
+	}
+}
+
+func (s *variableSuite) TestReadDefaultValue_ReferenceOtherBlockShouldReturnError() {
+	s.dummyFsWithFiles(map[string]string{
+		"test.hcl": `variable "other" {
+  default = "hello"
+}
+
+variable "test" {
+  default = upper(var.other)
+}`,
+	})
+	_, err := BuildDummyConfig("/", "", nil, nil)
+	require.NotNil(s.T(), err)
+	s.Contains(err.Error(), "default value of var.test cannot reference `var.other`")
+	s.Contains(err.Error(), "test.hcl:6,19-28")
+}
+
 func (s *variableSuite) TestReadValueFromEnv_EmptyEnvShouldReturnNilCtyValue() {
 	config, err := NewDummyConfig(".", context.TODO(), nil, nil)
 	require.NoError(s.T(), err)
@@ -156,14 +222,16 @@ func (s *variableSuite) TestReadValueFromEnv_EmptyEnvShouldReturnNilCtyValue() {
 
 func (s *variableSuite) TestReadVariableValue_ReadDefaultIfNotSet() {
 	cases := []struct {
-		desc     string
-		cliFlags []CliFlagAssignedVariables
-		files    map[string]string
-		expected VariableValueRead
+		desc           string
+		cliFlags       []CliFlagAssignedVariables
+		files          map[string]string
+		expected       VariableValueRead
+		expectedSource VariableValueSourceType
 	}{
 		{
-			desc:     "no value set",
-			expected: NewVariableValueRead("string_value", p(cty.StringVal("world")), nil),
+			desc:           "no value set",
+			expected:       NewVariableValueRead("string_value", p(cty.StringVal("world")), nil),
+			expectedSource: VariableValueSourceDefault,
 		},
 		{
 			desc: "CliFlagAssignedVariableFile-hcl",
@@ -173,7 +241,8 @@ func (s *variableSuite) TestReadVariableValue_ReadDefaultIfNotSet() {
 			files: map[string]string{
 				"/test.tfvars": `string_value = "hello"`,
 			},
-			expected: NewVariableValueRead("string_value", p(cty.StringVal("hello")), nil),
+			expected:       NewVariableValueRead("string_value", p(cty.StringVal("hello")), nil),
+			expectedSource: VariableValueSourceCliFlagVarFile,
 		},
 	}
 
@@ -192,7 +261,10 @@ func (s *variableSuite) TestReadVariableValue_ReadDefaultIfNotSet() {
 			vb := variableBlocks[0]
 			read, err := vb.readValue()
 			require.NoError(s.T(), err)
-			s.Equal(c.expected, read)
+			s.Equal(c.expected.Name, read.Name)
+			s.Equal(c.expected.Value, read.Value)
+			s.Equal(c.expected.Error, read.Error)
+			s.Equal(c.expectedSource, read.Source.Type)
 		})
 	}
 }
@@ -382,6 +454,136 @@ func (s *variableSuite) TestExecuteBeforePlan_Validation() {
 	}
 }
 
+func (s *variableSuite) TestValidation_ReferenceOtherVariablesAndLocals() {
+	cases := []struct {
+		desc                      string
+		config                    string
+		expectedErrorMessageRegex *string
+	}{
+		{
+			desc: "reference other variable",
+			config: `variable "min" {
+  default = 1
+}
+variable "max" {
+  default = 2
+  validation {
+    condition     = var.max > var.min
+    error_message = "max must be greater than min"
+  }
+}`,
+		},
+		{
+			desc: "reference other variable, invalid",
+			config: `variable "min" {
+  default = 3
+}
+variable "max" {
+  default = 2
+  validation {
+    condition     = var.max > var.min
+    error_message = "max must be greater than min"
+  }
+}`,
+			expectedErrorMessageRegex: p("max must be greater than min"),
+		},
+		{
+			desc: "reference local",
+			config: `locals {
+  allowed = ["a", "b"]
+}
+variable "test" {
+  default = "a"
+  validation {
+    condition     = contains(local.allowed, var.test)
+    error_message = "var.test must be allowed"
+  }
+}`,
+		},
+		{
+			desc: "validations reference each other",
+			config: `variable "a" {
+  default = 1
+  validation {
+    condition     = var.a != var.b
+    error_message = "a must not equal to b"
+  }
+}
+variable "b" {
+  default = 2
+  validation {
+    condition     = var.b != var.a
+    error_message = "b must not equal to a"
+  }
+}`,
+			expectedErrorMessageRegex: p("dependency cycle"),
+		},
+	}
+	for _, c := range cases {
+		s.Run(c.desc, func() {
+			s.dummyFsWithFiles(map[string]string{
+				"test.hcl": c.config,
+			})
+			_, err := BuildDummyConfig("/", "", nil, nil)
+			if c.expectedErrorMessageRegex != nil {
+				require.NotNil(s.T(), err)
+				s.Regexp(regexp.MustCompile(*c.expectedErrorMessageRegex), err.Error())
+				return
+			}
+			require.NoError(s.T(), err)
+		})
+	}
+}
+
+func (s *variableSuite) TestValidation_ReferencePlanOnlyBlockShouldBeDeferredToPlan() {
+	s.dummyFsWithFiles(map[string]string{
+		"test.hcl": `data "dummy" foo {
+}
+variable "test" {
+  default = "default_value"
+  validation {
+    condition     = var.test == data.dummy.foo.attribute
+    error_message = "var.test must equal to data.dummy.foo.attribute"
+  }
+}`,
+	})
+	config, err := BuildDummyConfig("/", "", nil, nil)
+	require.NoError(s.T(), err)
+	vb := config.GetVertices()["var.test"].(*VariableBlock)
+	s.Nil(vb.variableValue)
+	_, err = RunDummyPlan(config)
+	require.NoError(s.T(), err)
+	s.Equal(cty.StringVal("default_value"), *vb.variableValue)
+}
+
+func (s *variableSuite) TestValidation_UseOverrideFunctions() {
+	s.dummyFsWithFiles(map[string]string{
+		"test.hcl": `variable "test" {
+  default = "valid"
+  validation {
+    condition     = is_valid(var.test)
+    error_message = "var.test must be valid"
+  }
+}`,
+	})
+	hclBlocks, err := loadHclBlocks(false, "")
+	require.NoError(s.T(), err)
+	config := &DummyConfig{
+		BaseConfig: NewBasicConfig("/", "faketerraform", "ft", nil, nil, context.TODO()),
+	}
+	config.OverrideFunctions = map[string]function.Function{
+		"is_valid": function.New(&function.Spec{
+			Params: []function.Parameter{{Name: "input", Type: cty.String}},
+			Type:   function.StaticReturnType(cty.Bool),
+			Impl: func(args []cty.Value, retType cty.Type) (cty.Value, error) {
+				return cty.BoolVal(args[0].AsString() == "valid"), nil
+			},
+		}),
+	}
+	err = InitConfig(config, hclBlocks)
+	s.NoError(err)
+}
+
 func (s *variableSuite) TestExecuteBeforePlan_LocalWithVariable() {
 	cfg := `locals {
               a = "a"
@@ -400,6 +602,69 @@ func (s *variableSuite) TestExecuteBeforePlan_LocalWithVariable() {
 	s.Equal(cty.True, *c.GetVertices()["var.test"].(*VariableBlock).variableValue)
 }
 
+func (s *variableSuite) TestSensitiveVariable() {
+	s.dummyFsWithFiles(map[string]string{
+		"/main.hcl": `variable "password" {
+  type      = string
+  default   = "hunter2"
+  sensitive = true
+  validation {
+    condition     = length(var.password) > 3
+    error_message = "password is too short"
+  }
+}
+
+locals {
+  password = var.password
+}
+
+resource "dummy" "foo" {
+  tags = {
+    password = var.password
+  }
+}
+`,
+	})
+	c, err := BuildDummyConfig("/", "/", nil, nil)
+	require.NoError(s.T(), err)
+	require.NoError(s.T(), c.RunPlan())
+	config := c.(*DummyConfig)
+
+	password := config.EvalContext().Variables["local"].GetAttr("password")
+	s.True(password.HasMark(marks.Sensitive))
+	result, err := config.Eval("var.password")
+	require.NoError(s.T(), err)
+	s.Equal("(sensitive value)", result.String())
+
+	j, err := config.PlanResult().JSON()
+	require.NoError(s.T(), err)
+	var plan struct {
+		Blocks []struct {
+			Address string         `json:"address"`
+			Values  map[string]any `json:"values"`
+		} `json:"blocks"`
+	}
+	require.NoError(s.T(), json.Unmarshal(j, &plan))
+	redacted := make(map[string]any)
+	for _, b := range plan.Blocks {
+		if b.Address == "var.password" || b.Address == "local.password" {
+			redacted[b.Address] = b.Values["value"]
+		}
+	}
+	s.Equal(map[string]any{"var.password": nil, "local.password": nil}, redacted)
+
+	require.NoError(s.T(), config.SavePlan("/plan.json"))
+	content, err := afero.ReadFile(s.fs, "/plan.json")
+	require.NoError(s.T(), err)
+	var saved SavedPlan
+	require.NoError(s.T(), json.Unmarshal(content, &saved))
+	s.True(saved.Variables["password"].HasMark(marks.Sensitive))
+	applied, err := BuildDummyConfig("/", "/", nil, nil)
+	require.NoError(s.T(), err)
+	require.NoError(s.T(), applied.(*DummyConfig).LoadPlan("/plan.json"))
+	s.True(applied.EvalContext().Variables["var"].GetAttr("password").HasMark(marks.Sensitive))
+}
+
 var _ variableValuePromoter = &mockVariableValuePromoter{}
 
 type mockVariableValuePromoter struct {
@@ -0,0 +1,71 @@
+package golden
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+)
+
+var workspaceNameRegex = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9_.-]*$`)
+
+// ValidateWorkspaceName checks name could be used as part of var file names and artifact directories.
+func ValidateWorkspaceName(name string) error {
+	if !workspaceNameRegex.MatchString(name) || name == "." || name == ".." {
+		return fmt.Errorf("invalid workspace name %q, it must start with a letter or digit and contain only letters, digits, `_`, `-` and `.`", name)
+	}
+	return nil
+}
+
+// WorkspaceDir returns the directory for persisted artifacts like plan and state files of the selected workspace,
+// it's `<root dir>/.<dsl full name>/workspaces/<workspace>`, so workspaces never share artifacts.
+func (c *BaseConfig) WorkspaceDir() (string, error) {
+	ws := c.Workspace()
+	if err := ValidateWorkspaceName(ws); err != nil {
+		return "", err
+	}
+	return filepath.Join(c.rootDir, "."+c.dslFullName, "workspaces", ws), nil
+}
+
+// WorkspaceArtifactPath returns path of artifact name in WorkspaceDir.
+func (c *BaseConfig) WorkspaceArtifactPath(name string) (string, error) {
+	dir, err := c.WorkspaceDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, name), nil
+}
+
+// workspacePath resolves a relative plan or state file path under WorkspaceDir, so workspaces never share them by default.
+// Absolute paths are used as given.
+func (c *BaseConfig) workspacePath(path string) (string, error) {
+	if path == "" || filepath.IsAbs(path) {
+		return path, nil
+	}
+	return c.WorkspaceArtifactPath(path)
+}
+
+// resolveWorkspacePaths resolves StatePath and SavedPlanPath by workspacePath, it's called once by InitConfig.
+func (c *BaseConfig) resolveWorkspacePaths() error {
+	var err error
+	if c.statePath, err = c.workspacePath(c.statePath); err != nil {
+		return err
+	}
+	c.savedPlanPath, err = c.workspacePath(c.savedPlanPath)
+	return err
+}
+
+// workspaceVarFilePaths returns `<workspace>.<abbr>vars` files, they're read after the default var files so they take precedence.
+func (c *BaseConfig) workspaceVarFilePaths() ([]string, error) {
+	if c.Workspace() == defaultWorkspace {
+		return nil, nil
+	}
+	if err := ValidateWorkspaceName(c.workspace); err != nil {
+		return nil, err
+	}
+	path := filepath.Join(c.variableConfigFilesDir(), fmt.Sprintf("%s.%svars", c.workspace, c.dslAbbreviation))
+	var paths []string
+	for _, suffix := range varFileSuffixes {
+		paths = append(paths, path+suffix)
+	}
+	return paths, nil
+}
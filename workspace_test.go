@@ -0,0 +1,171 @@
+package golden
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"github.com/zclconf/go-cty/cty"
+)
+
+type workspaceSuite struct {
+	suite.Suite
+	*testBase
+}
+
+func TestWorkspaceSuite(t *testing.T) {
+	suite.Run(t, new(workspaceSuite))
+}
+
+func (s *workspaceSuite) SetupTest() {
+	s.testBase = newTestBase()
+}
+
+func (s *workspaceSuite) TearDownTest() {
+	s.teardown()
+}
+
+func (s *workspaceSuite) workspaceConfig(workspace string) (*DummyConfig, error) {
+	hclBlocks, err := loadHclBlocks(false, "/")
+	if err != nil {
+		return nil, err
+	}
+	c := &DummyConfig{
+		BaseConfig: NewBasicConfigFromArgs(NewBaseConfigArgs{
+			Basedir:         "/",
+			DslFullName:     "faketerraform",
+			DslAbbreviation: "ft",
+			Workspace:       workspace,
+		}),
+	}
+	return c, InitConfig(c, hclBlocks)
+}
+
+func (s *workspaceSuite) TestWorkspace_VarFilesAndValue() {
+	s.dummyFsWithFiles(map[string]string{
+		"/main.hcl": `variable "env" {
+  default = "from_default"
+}
+
+variable "region" {
+  default = "from_default"
+}
+
+locals {
+  workspace = faketerraform.workspace
+}
+`,
+		"/faketerraform.ftvars": `env = "from_default_var_file"
+region = "from_default_var_file"`,
+		"/prod.ftvars":      `env = "prod"`,
+		"/test.ftvars.json": `{"env": "test"}`,
+	})
+	cases := []struct {
+		workspace string
+		env       string
+	}{
+		{workspace: "", env: "from_default_var_file"},
+		{workspace: "prod", env: "prod"},
+		{workspace: "test", env: "test"},
+		{workspace: "dev", env: "from_default_var_file"},
+	}
+	for _, c := range cases {
+		s.Run(c.workspace, func() {
+			config, err := s.workspaceConfig(c.workspace)
+			require.NoError(s.T(), err)
+			_, err = RunDummyPlan(config)
+			require.NoError(s.T(), err)
+			ctx := config.EvalContext()
+			s.Equal(cty.StringVal(c.env), ctx.Variables["var"].GetAttr("env"))
+			s.Equal(cty.StringVal("from_default_var_file"), ctx.Variables["var"].GetAttr("region"))
+			s.Equal(cty.StringVal(config.Workspace()), ctx.Variables["local"].GetAttr("workspace"))
+		})
+	}
+}
+
+func (s *workspaceSuite) TestWorkspace_ArtifactDirIsolatedPerWorkspace() {
+	dev := NewBasicConfigFromArgs(NewBaseConfigArgs{Basedir: "/cfg", DslFullName: "faketerraform", Workspace: "dev"})
+	prod := NewBasicConfigFromArgs(NewBaseConfigArgs{Basedir: "/cfg", DslFullName: "faketerraform", Workspace: "prod"})
+	def := NewBasicConfigFromArgs(NewBaseConfigArgs{Basedir: "/cfg", DslFullName: "faketerraform"})
+	devPath, err := dev.WorkspaceArtifactPath("state.json")
+	require.NoError(s.T(), err)
+	s.Equal("/cfg/.faketerraform/workspaces/dev/state.json", devPath)
+	prodDir, err := prod.WorkspaceDir()
+	require.NoError(s.T(), err)
+	s.Equal("/cfg/.faketerraform/workspaces/prod", prodDir)
+	defDir, err := def.WorkspaceDir()
+	require.NoError(s.T(), err)
+	s.Equal("/cfg/.faketerraform/workspaces/default", defDir)
+}
+
+func (s *workspaceSuite) TestWorkspace_InvalidName() {
+	for _, name := range []string{"../prod", "a/b", ".hidden", ".."} {
+		s.ErrorContains(ValidateWorkspaceName(name), "invalid workspace name")
+		c := NewBasicConfigFromArgs(NewBaseConfigArgs{Basedir: "/cfg", DslFullName: "faketerraform", Workspace: name})
+		_, err := c.WorkspaceDir()
+		s.ErrorContains(err, "invalid workspace name")
+	}
+	s.dummyFsWithFiles(map[string]string{
+		"/main.hcl": `variable "env" {
+  default = "from_default"
+}`,
+	})
+	_, err := s.workspaceConfig("../prod")
+	s.ErrorContains(err, "invalid workspace name")
+}
+
+func (s *workspaceSuite) TestWorkspace_RelativeArtifactPathsIsolated() {
+	s.dummyFsWithFiles(map[string]string{
+		"/main.hcl": `variable "env" {}
+
+resource "dummy" "foo" {
+  tags = {
+    env = var.env
+  }
+}
+`,
+		"/dev.ftvars":  `env = "dev"`,
+		"/prod.ftvars": `env = "prod"`,
+	})
+	newConfig := func(workspace string) *DummyConfig {
+		hclBlocks, err := loadHclBlocks(false, "/")
+		require.NoError(s.T(), err)
+		c := &DummyConfig{
+			BaseConfig: NewBasicConfigFromArgs(NewBaseConfigArgs{
+				Basedir:         "/",
+				DslFullName:     "faketerraform",
+				DslAbbreviation: "ft",
+				Workspace:       workspace,
+				StatePath:       "state.json",
+			}),
+		}
+		require.NoError(s.T(), InitConfig(c, hclBlocks))
+		return c
+	}
+	for _, ws := range []string{"dev", "prod"} {
+		c := newConfig(ws)
+		require.NoError(s.T(), c.RunPlan())
+		require.NoError(s.T(), c.SavePlan("plan.json"))
+	}
+	for _, ws := range []string{"dev", "prod"} {
+		exist, err := afero.Exists(s.fs, fmt.Sprintf("/.faketerraform/workspaces/%s/plan.json", ws))
+		require.NoError(s.T(), err)
+		s.True(exist, ws)
+		c := newConfig(ws)
+		require.NoError(s.T(), c.LoadPlan("plan.json"))
+		require.NoError(s.T(), c.RunApply())
+	}
+	for _, ws := range []string{"dev", "prod"} {
+		content, err := afero.ReadFile(s.fs, fmt.Sprintf("/.faketerraform/workspaces/%s/state.json", ws))
+		require.NoError(s.T(), err)
+		var state State
+		require.NoError(s.T(), json.Unmarshal(content, &state))
+		s.Equal(cty.MapVal(map[string]cty.Value{"env": cty.StringVal(ws)}), state.Blocks["resource.dummy.foo"].Values["tags"].Value, ws)
+	}
+	exist, err := afero.Exists(s.fs, "/state.json")
+	require.NoError(s.T(), err)
+	s.False(exist)
+}